@@ -0,0 +1,164 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// tokenRefreshMargin is how long before expiry a bearer token is refreshed.
+const tokenRefreshMargin = 30 * time.Second
+
+// HeaderFunc adds request headers, such as credentials, to an outgoing request.
+// It is called for every HTTP request and for every WebSocket handshake.
+type HeaderFunc func(h http.Header) error
+
+// TokenSource returns a bearer token and when it expires; a zero expiry never expires.
+type TokenSource func(ctx context.Context) (token string, expiry time.Time, err error)
+
+// WithHeader sets a header, such as an API key, on every request and WebSocket handshake.
+func WithHeader(key, value string) DialOption {
+	return func(cfg *dialConfig) {
+		if cfg.headers == nil {
+			cfg.headers = make(http.Header)
+		}
+		cfg.headers.Set(key, value)
+	}
+}
+
+// WithHeaders sets the given headers on every request and WebSocket handshake.
+func WithHeaders(headers http.Header) DialOption {
+	return func(cfg *dialConfig) {
+		if cfg.headers == nil {
+			cfg.headers = make(http.Header)
+		}
+		for key, values := range headers {
+			cfg.headers[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+		}
+	}
+}
+
+// WithHTTPAuth calls fn to add credentials to every request and WebSocket handshake.
+func WithHTTPAuth(fn HeaderFunc) DialOption {
+	return func(cfg *dialConfig) {
+		cfg.auth = fn
+		cfg.token = nil
+	}
+}
+
+// WithBearerToken sends "Authorization: Bearer <token>" using tokens from src.
+// A token is reused until shortly before it expires, and refreshed once when the
+// endpoint answers an HTTP request with 401 Unauthorized.
+func WithBearerToken(src TokenSource) DialOption {
+	return func(cfg *dialConfig) {
+		cfg.token = &tokenCache{src: src}
+		cfg.auth = nil
+	}
+}
+
+// tokenCache holds the current bearer token of a TokenSource.
+type tokenCache struct {
+	src TokenSource
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// get returns a valid token, fetching a new one if needed.
+func (tc *tokenCache) get(ctx context.Context) (string, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.token != "" && (tc.expiry.IsZero() || time.Until(tc.expiry) > tokenRefreshMargin) {
+		return tc.token, nil
+	}
+	token, expiry, err := tc.src(ctx)
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", errors.New("token source returned an empty token")
+	}
+	tc.token, tc.expiry = token, expiry
+	return token, nil
+}
+
+// invalidate drops token so the next request fetches a new one; other tokens are kept.
+func (tc *tokenCache) invalidate(token string) {
+	tc.mu.Lock()
+	if tc.token == token {
+		tc.token = ""
+	}
+	tc.mu.Unlock()
+}
+
+// header is the HeaderFunc used for WebSocket handshakes.
+func (tc *tokenCache) header(h http.Header) error {
+	token, err := tc.get(context.Background())
+	if err != nil {
+		return err
+	}
+	h.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// headerTransport returns a RoundTripper that adds the configured headers and credentials.
+func (cfg *dialConfig) headerTransport(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := readRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+		r, err := cfg.withHeaders(req, body)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := next.RoundTrip(r)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized || cfg.token == nil {
+			return resp, err
+		}
+
+		// The token may have been revoked early; refresh it and try once more
+		cfg.token.invalidate(bearerToken(r.Header))
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if r, err = cfg.withHeaders(req, body); err != nil {
+			return nil, err
+		}
+		return next.RoundTrip(r)
+	})
+}
+
+// withHeaders clones req with a fresh body and the configured headers applied.
+func (cfg *dialConfig) withHeaders(req *http.Request, body []byte) (*http.Request, error) {
+	r := withEndpoint(req, req.URL, body)
+	for key, values := range cfg.headers {
+		r.Header[key] = values
+	}
+	switch {
+	case cfg.token != nil:
+		token, err := cfg.token.get(r.Context())
+		if err != nil {
+			return nil, err
+		}
+		r.Header.Set("Authorization", "Bearer "+token)
+	case cfg.auth != nil:
+		if err := cfg.auth(r.Header); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// bearerToken extracts the token of a bearer Authorization header.
+func bearerToken(h http.Header) string {
+	const prefix = "Bearer "
+	v := h.Get("Authorization")
+	if len(v) > len(prefix) && v[:len(prefix)] == prefix {
+		return v[len(prefix):]
+	}
+	return ""
+}
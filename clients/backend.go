@@ -0,0 +1,156 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// backendURL is the placeholder endpoint of clients built on a Backend; it is never dialed.
+const backendURL = "http://backend.invalid"
+
+// Backend is the JSON-RPC connection a Client sends its requests through.
+// *rpc.Client, FakeBackend, Replayer and the simulated package's Backend implement it.
+type Backend interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+	Close()
+}
+
+var (
+	_ Backend = (*rpc.Client)(nil)
+	_ Backend = (*FakeBackend)(nil)
+	_ Backend = (*Replayer)(nil)
+)
+
+// RPCBackend is implemented by backends that are served by an *rpc.Client,
+// which the Client then uses directly, native subscriptions included.
+type RPCBackend interface {
+	Backend
+	RPCClient() *rpc.Client
+}
+
+// NewClientFromBackend creates a client whose requests are served by b, e.g. a FakeBackend
+// in unit tests. Retry, cache, rate-limit and instrumentation options apply to its calls;
+// subscriptions are polled unless b is an RPCBackend. Close closes b.
+func NewClientFromBackend(b Backend, opts ...DialOption) (*Client, error) {
+	if b == nil {
+		return nil, errors.New("backend is nil")
+	}
+	switch b := b.(type) {
+	case *rpc.Client:
+		return NewClientFromRPC(b, opts...)
+	case RPCBackend:
+		cfg := newDialConfig(opts)
+		c, err := cfg.clientFromRPC(b.RPCClient())
+		if err != nil {
+			b.Close()
+			return nil, err
+		}
+		c.onClose = append(c.onClose, b.Close)
+		return cfg.attach(c)
+	}
+
+	cfg := newDialConfig(opts)
+	transport := cfg.transport(cfg.endpointTransport(backendTransport(b)))
+	rpcClient, err := rpc.DialOptions(context.Background(), backendURL, rpc.WithHTTPClient(cfg.newHTTPClient(transport)))
+	if err != nil {
+		return nil, err
+	}
+	c := newClient(rpcClient, nil)
+	c.onClose = append(c.onClose, b.Close)
+	return cfg.attach(c)
+}
+
+// backendTransport returns a RoundTripper that answers JSON-RPC requests by calling b.
+func backendTransport(b Backend) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := readRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+		msgs, batch, err := parseRPCMessages(body)
+		if err != nil {
+			return nil, err
+		}
+
+		elems := make([]rpc.BatchElem, len(msgs))
+		for i, msg := range msgs {
+			var params []json.RawMessage
+			if len(msg.Params) > 0 {
+				if err := json.Unmarshal(msg.Params, &params); err != nil {
+					return nil, err
+				}
+			}
+			args := make([]interface{}, len(params))
+			for j, p := range params {
+				args[j] = p
+			}
+			elems[i] = rpc.BatchElem{Method: msg.Method, Args: args, Result: new(json.RawMessage)}
+		}
+		if batch {
+			err = b.BatchCallContext(req.Context(), elems)
+		} else {
+			err = b.CallContext(req.Context(), elems[0].Result, elems[0].Method, elems[0].Args...)
+			elems[0].Error = err
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		replies := make([]rpcMessage, len(msgs))
+		for i, elem := range elems {
+			replies[i] = rpcMessage{Version: "2.0", ID: msgs[i].ID}
+			if elem.Error != nil {
+				replies[i].Error = toRPCError(elem.Error)
+				continue
+			}
+			replies[i].Result = *elem.Result.(*json.RawMessage)
+			if len(replies[i].Result) == 0 {
+				replies[i].Result = json.RawMessage("null")
+			}
+		}
+		var out []byte
+		if batch {
+			out, err = json.Marshal(replies)
+		} else {
+			out, err = json.Marshal(replies[0])
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			Body:          io.NopCloser(bytes.NewReader(out)),
+			ContentLength: int64(len(out)),
+			Request:       req,
+		}, nil
+	})
+}
+
+// toRPCError converts a backend error into a JSON-RPC error object, keeping its code and data.
+func toRPCError(err error) *rpcError {
+	e := &rpcError{Code: -32000, Message: err.Error()}
+	var coded rpc.Error
+	if errors.As(err, &coded) {
+		e.Code = coded.ErrorCode()
+	}
+	var withData rpc.DataError
+	if errors.As(err, &withData) && withData.ErrorData() != nil {
+		if data, mErr := json.Marshal(withData.ErrorData()); mErr == nil {
+			e.Data = data
+		}
+	}
+	return e
+}
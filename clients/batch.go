@@ -0,0 +1,163 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultBatchSize is the largest number of calls sent in one JSON-RPC batch.
+// Bigger batches are split into several requests.
+const DefaultBatchSize = 100
+
+// errBatchNotExecuted is reported by results read before Execute has run.
+var errBatchNotExecuted = errors.New("batch not executed")
+
+// Batch queues read calls and sends them as JSON-RPC batches.
+// Queue calls, run Execute, then read each BatchResult.
+type Batch struct {
+	client  *Client
+	maxSize int
+	elems   []rpc.BatchElem
+	decode  []func(json.RawMessage, error)
+}
+
+// BatchResult holds the outcome of one queued call once the batch has executed.
+type BatchResult[T any] struct {
+	Value T
+	Err   error
+}
+
+// Result returns the decoded value and the per-call error.
+func (r *BatchResult[T]) Result() (T, error) {
+	return r.Value, r.Err
+}
+
+// NewBatch creates an empty batch bound to the client.
+// The batch is split into requests of at most DefaultBatchSize calls.
+func (c *Client) NewBatch() *Batch {
+	return &Batch{client: c, maxSize: DefaultBatchSize}
+}
+
+// SetMaxSize sets how many calls are sent per JSON-RPC request.
+// Values below 1 restore DefaultBatchSize.
+func (b *Batch) SetMaxSize(n int) *Batch {
+	if n < 1 {
+		n = DefaultBatchSize
+	}
+	b.maxSize = n
+	return b
+}
+
+// Len returns the number of queued calls.
+func (b *Batch) Len() int {
+	return len(b.elems)
+}
+
+// BalanceAt queues an eth_getBalance call for the account at the latest block,
+// or at the block selected by opts.
+func (b *Batch) BalanceAt(account common.Address, opts ...CallOption) *BatchResult[*big.Int] {
+	res := &BatchResult[*big.Int]{Err: errBatchNotExecuted}
+	b.queue(func(raw json.RawMessage, err error) {
+		var out hexutil.Big
+		if res.Err = decodeBatchResult(raw, err, &out); res.Err == nil {
+			res.Value = out.ToInt()
+		}
+	}, "eth_getBalance", account, newCallConfig(opts).blockArg())
+	return res
+}
+
+// NonceAt queues an eth_getTransactionCount call for the account at the latest block,
+// or at the block selected by opts.
+func (b *Batch) NonceAt(account common.Address, opts ...CallOption) *BatchResult[uint64] {
+	res := &BatchResult[uint64]{Err: errBatchNotExecuted}
+	b.queue(func(raw json.RawMessage, err error) {
+		var out hexutil.Uint64
+		if res.Err = decodeBatchResult(raw, err, &out); res.Err == nil {
+			res.Value = uint64(out)
+		}
+	}, "eth_getTransactionCount", account, newCallConfig(opts).blockArg())
+	return res
+}
+
+// CallContract queues an eth_call against the latest block, or the block selected by opts.
+// Use the contract ABI to unpack the returned bytes.
+func (b *Batch) CallContract(msg ethereum.CallMsg, opts ...CallOption) *BatchResult[[]byte] {
+	res := &BatchResult[[]byte]{Err: errBatchNotExecuted}
+	b.queue(func(raw json.RawMessage, err error) {
+		var out hexutil.Bytes
+		if res.Err = decodeBatchResult(raw, err, &out); res.Err == nil {
+			res.Value = out
+		}
+	}, "eth_call", toCallArg(msg, nil), newCallConfig(opts).blockArg())
+	return res
+}
+
+// Call queues an arbitrary RPC method. The raw JSON result can be decoded with json.Unmarshal.
+// A null result is reported as ethereum.NotFound.
+func (b *Batch) Call(method string, args ...interface{}) *BatchResult[json.RawMessage] {
+	res := &BatchResult[json.RawMessage]{Err: errBatchNotExecuted}
+	b.queue(func(raw json.RawMessage, err error) {
+		if res.Err = err; err == nil {
+			if len(raw) == 0 || string(raw) == "null" {
+				res.Err = ethereum.NotFound
+				return
+			}
+			res.Value = raw
+		}
+	}, method, args...)
+	return res
+}
+
+// Execute sends every queued call, splitting them into requests of at most the max size.
+// Per-call failures are reported on each BatchResult; the returned error is the first
+// transport failure, in which case every call of the failed request carries that error.
+func (b *Batch) Execute(ctx context.Context) error {
+	var firstErr error
+	for start := 0; start < len(b.elems); start += b.maxSize {
+		end := start + b.maxSize
+		if end > len(b.elems) {
+			end = len(b.elems)
+		}
+		chunk := b.elems[start:end]
+		err := b.client.RpcClient.BatchCallContext(ctx, chunk)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		for i := range chunk {
+			callErr := chunk[i].Error
+			if err != nil {
+				callErr = err
+			}
+			b.decode[start+i](*chunk[i].Result.(*json.RawMessage), callErr)
+		}
+	}
+	return firstErr
+}
+
+// queue adds one call with the function that decodes its result.
+func (b *Batch) queue(decode func(json.RawMessage, error), method string, args ...interface{}) {
+	b.elems = append(b.elems, rpc.BatchElem{
+		Method: method,
+		Args:   args,
+		Result: new(json.RawMessage),
+	})
+	b.decode = append(b.decode, decode)
+}
+
+// decodeBatchResult unmarshals a non-null raw result into out.
+func decodeBatchResult(raw json.RawMessage, err error, out interface{}) error {
+	if err != nil {
+		return err
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return ethereum.NotFound
+	}
+	return json.Unmarshal(raw, out)
+}
@@ -0,0 +1,119 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestBatchMapsResultsAndErrors(t *testing.T) {
+	a := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	b := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	fake := NewFakeBackend().
+		OnFunc("eth_getBalance", func(params []json.RawMessage) (interface{}, error) {
+			if strings.Contains(strings.ToLower(string(params[0])), "bb") {
+				return "0x2", nil
+			}
+			return "0x1", nil
+		}).
+		On("eth_getTransactionCount", "0x7").
+		OnError("eth_call", &FixtureError{Code: 3, Message: "execution reverted"}).
+		On("eth_getBlockByHash", nil)
+	client, err := NewClientFromBackend(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	batch := client.NewBatch().SetMaxSize(2)
+	balanceA := batch.BalanceAt(a)
+	balanceB := batch.BalanceAt(b, AtBlockNumber(5))
+	nonce := batch.NonceAt(a)
+	call := batch.CallContract(ethereum.CallMsg{To: &b})
+	block := batch.Call("eth_getBlockByHash", common.Hash{}, false)
+	if batch.Len() != 5 {
+		t.Fatalf("Len = %d, want 5", batch.Len())
+	}
+	if _, err := balanceA.Result(); !errors.Is(err, errBatchNotExecuted) {
+		t.Fatalf("before Execute: err = %v, want errBatchNotExecuted", err)
+	}
+
+	if err := batch.Execute(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := balanceA.Result(); err != nil || v.Int64() != 1 {
+		t.Fatalf("balance of a = %v, %v; want 1", v, err)
+	}
+	if v, err := balanceB.Result(); err != nil || v.Int64() != 2 {
+		t.Fatalf("balance of b = %v, %v; want 2", v, err)
+	}
+	if v, err := nonce.Result(); err != nil || v != 7 {
+		t.Fatalf("nonce = %v, %v; want 7", v, err)
+	}
+	var coded rpc.Error
+	if _, err := call.Result(); !errors.As(err, &coded) || coded.ErrorCode() != 3 {
+		t.Fatalf("call err = %v, want code 3", err)
+	}
+	if _, err := block.Result(); !errors.Is(err, ethereum.NotFound) {
+		t.Fatalf("null result: err = %v, want ethereum.NotFound", err)
+	}
+
+	calls := fake.CallsTo("eth_getBalance")
+	if len(calls) != 2 || string(calls[1].Params[1]) != `"0x5"` {
+		t.Fatalf("eth_getBalance calls = %+v, want the second at block 0x5", calls)
+	}
+}
+
+func TestBatchTransportErrorFailsItsRequest(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 2 {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return
+		}
+		var reqs []rpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		replies := make([]rpcMessage, len(reqs))
+		for i, req := range reqs {
+			replies[i] = rpcMessage{Version: "2.0", ID: req.ID, Result: json.RawMessage(`"0x1"`)}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(replies)
+	}))
+	defer srv.Close()
+	client, err := Dial(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	batch := client.NewBatch().SetMaxSize(2)
+	var results []*BatchResult[uint64]
+	for i := 0; i < 4; i++ {
+		results = append(results, batch.NonceAt(common.Address{byte(i)}))
+	}
+	if err := batch.Execute(context.Background()); err == nil {
+		t.Fatal("Execute: want the transport error")
+	}
+	for i, res := range results {
+		v, err := res.Result()
+		if i < 2 && (err != nil || v != 1) {
+			t.Fatalf("call %d = %d, %v; want 1 from the first request", i, v, err)
+		}
+		if i >= 2 && err == nil {
+			t.Fatalf("call %d succeeded, want the second request's error", i)
+		}
+	}
+}
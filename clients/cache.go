@@ -0,0 +1,440 @@
+package clients
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultCacheEntries is the default number of responses kept by the cache.
+const DefaultCacheEntries = 4096
+
+// CacheConfig configures the read-call response cache.
+type CacheConfig struct {
+	MaxEntries int // LRU capacity; defaults to DefaultCacheEntries
+}
+
+// CacheStats reports response cache activity. Each call of a batch counts on its own.
+type CacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+}
+
+// WithCache caches the results of read calls (eth_call, eth_getBalance, eth_getTransactionCount,
+// eth_getCode, eth_getStorageAt, eth_chainId). Applies to calls on every transport.
+//
+// Results at a block number or hash are kept until evicted; results at "latest", "safe"
+// or "finalized" are dropped when a new head arrives; "pending" is never cached. Token
+// name, symbol and decimals calls are kept for the life of the client.
+func WithCache(cfg CacheConfig) DialOption {
+	return func(dc *dialConfig) {
+		dc.cacheConfig = &cfg
+	}
+}
+
+// CacheStats returns hit and miss counters of the response cache.
+// Always zero when the client was dialed without WithCache.
+func (c *Client) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return c.cache.stats()
+}
+
+type cacheScope int
+
+const (
+	cacheNever   cacheScope = iota
+	cacheHead               // valid until the next head
+	cacheForever            // valid until evicted
+)
+
+// cacheBlockArg is the position of the block parameter of each cacheable method.
+var cacheBlockArg = map[string]int{
+	"eth_call":                1,
+	"eth_getBalance":          1,
+	"eth_getTransactionCount": 1,
+	"eth_getCode":             1,
+	"eth_getStorageAt":        2,
+}
+
+// tokenMetadataSelectors are the argument-less ERC20/ERC721 getters whose results never change.
+var tokenMetadataSelectors = map[string]bool{
+	"0x06fdde03": true, // name()
+	"0x95d89b41": true, // symbol()
+	"0x313ce567": true, // decimals()
+}
+
+type cacheEntry struct {
+	key    string
+	result json.RawMessage
+	scope  cacheScope
+	epoch  uint64
+}
+
+type responseCache struct {
+	max int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	epoch   uint64 // bumped on every new head
+	heads   bool   // whether a head watcher is running
+	hits    uint64
+	misses  uint64
+}
+
+func newResponseCache(cfg CacheConfig) *responseCache {
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = DefaultCacheEntries
+	}
+	return &responseCache{
+		max:     cfg.MaxEntries,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// transport returns a RoundTripper that answers cacheable calls from the cache.
+// A batch is served from the cache only when every call in it is a hit.
+func (rc *responseCache) transport(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Context().Value(skipCacheKey{}) != nil {
+			return next.RoundTrip(req)
+		}
+		body, err := readRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+		reqs, batch, err := parseRPCMessages(body)
+		if err != nil {
+			return next.RoundTrip(req)
+		}
+
+		keys := make([]string, len(reqs))
+		scopes := make([]cacheScope, len(reqs))
+		for i, m := range reqs {
+			keys[i], scopes[i] = cacheKey(m.Method, m.Params)
+		}
+		if resp := rc.serve(req, reqs, keys, scopes, batch); resp != nil {
+			return resp, nil
+		}
+
+		epoch := rc.currentEpoch()
+		resp, err := next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			return resp, err
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		if err != nil {
+			return resp, nil
+		}
+		rc.store(reqs, keys, scopes, respBody, epoch)
+		return resp, nil
+	})
+}
+
+// serve builds a response from the cache if every call in the request is a hit.
+// Each call is counted as a hit or a miss, even when the request is sent on.
+func (rc *responseCache) serve(req *http.Request, reqs []rpcMessage, keys []string, scopes []cacheScope, batch bool) *http.Response {
+	results := make([]json.RawMessage, len(reqs))
+	rc.mu.Lock()
+	for i := range reqs {
+		if scopes[i] == cacheNever {
+			rc.mu.Unlock()
+			return nil
+		}
+		results[i] = rc.get(keys[i])
+	}
+	hit := true
+	for _, r := range results {
+		if r == nil {
+			hit = false
+			rc.misses++
+		} else {
+			rc.hits++
+		}
+	}
+	rc.mu.Unlock()
+	if !hit {
+		return nil
+	}
+
+	msgs := make([]rpcMessage, len(reqs))
+	for i, m := range reqs {
+		msgs[i] = rpcMessage{Version: "2.0", ID: m.ID, Result: results[i]}
+	}
+	var out []byte
+	if batch {
+		out, _ = json.Marshal(msgs)
+	} else {
+		out, _ = json.Marshal(msgs[0])
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(out)),
+		ContentLength: int64(len(out)),
+		Request:       req,
+	}
+}
+
+// store caches the successful results of a response, matched to requests by ID.
+func (rc *responseCache) store(reqs []rpcMessage, keys []string, scopes []cacheScope, respBody []byte, epoch uint64) {
+	resps, _, err := parseRPCMessages(respBody)
+	if err != nil {
+		return
+	}
+	byID := make(map[string]rpcMessage, len(resps))
+	for _, m := range resps {
+		byID[string(m.ID)] = m
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for i, m := range reqs {
+		if scopes[i] == cacheNever {
+			continue
+		}
+		if scopes[i] == cacheHead && (!rc.heads || epoch != rc.epoch) {
+			// No head tracking, or a head arrived while the call was in flight
+			continue
+		}
+		resp, ok := byID[string(m.ID)]
+		if !ok || resp.Error != nil || len(resp.Result) == 0 {
+			continue
+		}
+		if strings.HasPrefix(keys[i], "meta:") && string(resp.Result) == `"0x"` {
+			// No code at that block yet; the token may be deployed later
+			continue
+		}
+		rc.put(&cacheEntry{key: keys[i], result: resp.Result, scope: scopes[i], epoch: epoch})
+	}
+}
+
+// get returns a cached result, or nil; must hold rc.mu.
+func (rc *responseCache) get(key string) json.RawMessage {
+	el, ok := rc.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := el.Value.(*cacheEntry)
+	if entry.scope == cacheHead && entry.epoch != rc.epoch {
+		rc.remove(el)
+		return nil
+	}
+	rc.lru.MoveToFront(el)
+	return entry.result
+}
+
+// put inserts an entry, evicting the least recently used ones; must hold rc.mu.
+func (rc *responseCache) put(entry *cacheEntry) {
+	if el, ok := rc.entries[entry.key]; ok {
+		el.Value = entry
+		rc.lru.MoveToFront(el)
+		return
+	}
+	rc.entries[entry.key] = rc.lru.PushFront(entry)
+	for rc.lru.Len() > rc.max {
+		rc.remove(rc.lru.Back())
+	}
+}
+
+// remove drops an entry; must hold rc.mu.
+func (rc *responseCache) remove(el *list.Element) {
+	rc.lru.Remove(el)
+	delete(rc.entries, el.Value.(*cacheEntry).key)
+}
+
+// advance invalidates every result tied to the previous head.
+func (rc *responseCache) advance() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.epoch++
+	for el := rc.lru.Front(); el != nil; {
+		next := el.Next()
+		if el.Value.(*cacheEntry).scope == cacheHead {
+			rc.remove(el)
+		}
+		el = next
+	}
+}
+
+// setTracking records whether new heads are being observed.
+// Head-scoped results are only cached while they are.
+func (rc *responseCache) setTracking(on bool) {
+	rc.mu.Lock()
+	rc.heads = on
+	rc.mu.Unlock()
+	if !on {
+		rc.advance()
+	}
+}
+
+func (rc *responseCache) currentEpoch() uint64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.epoch
+}
+
+func (rc *responseCache) stats() CacheStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return CacheStats{Hits: rc.hits, Misses: rc.misses, Entries: rc.lru.Len()}
+}
+
+type skipCacheKey struct{}
+
+// skipCache marks ctx so that the response cache neither serves nor stores its calls.
+func skipCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipCacheKey{}, true)
+}
+
+// watchHeads advances the cache on every new head until ctx is done.
+// The subscription is re-established after errors. Without native subscriptions it
+// only polls eth_blockNumber, as the cache has no use for the headers.
+func (rc *responseCache) watchHeads(ctx context.Context, c *Client) {
+	attempt := 0 // consecutive failures
+	for {
+		var (
+			heads   = make(chan *types.Header, 16)
+			numbers = make(chan uint64, 16)
+			sub     ethereum.Subscription
+			err     error
+		)
+		if c.subEth != nil {
+			sub, err = c.SubscribeNewHeads(ctx, heads)
+		} else {
+			sub, err = c.pollHeadNumbers(ctx, numbers)
+		}
+		if err == nil {
+			attempt = 0
+			rc.advance()
+			rc.setTracking(true)
+		loop:
+			for {
+				select {
+				case <-heads:
+					rc.advance()
+				case <-numbers:
+					rc.advance()
+				case err = <-sub.Err():
+					break loop
+				case <-ctx.Done():
+					sub.Unsubscribe()
+					return
+				}
+			}
+			sub.Unsubscribe()
+			rc.setTracking(false)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		attempt++
+		c.Logger().LogAttrs(ctx, slog.LevelWarn, "cache head subscription failed, latest results are not cached",
+			slog.String(LogKeyKind, "newHeads"),
+			slog.Int(LogKeyAttempt, attempt),
+			slog.Any(LogKeyError, err))
+
+		timer := time.NewTimer(c.pollEvery())
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// cacheKey returns the cache key of a call and how long its result stays valid.
+func cacheKey(method string, params json.RawMessage) (string, cacheScope) {
+	if method == "eth_chainId" {
+		return method, cacheForever
+	}
+	idx, ok := cacheBlockArg[method]
+	if !ok {
+		return "", cacheNever
+	}
+	var args []json.RawMessage
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &args); err != nil {
+			return "", cacheNever
+		}
+	}
+
+	if method == "eth_call" && len(args) > 0 {
+		var call struct {
+			To    string        `json:"to"`
+			Data  hexutil.Bytes `json:"data"`
+			Input hexutil.Bytes `json:"input"`
+		}
+		if err := json.Unmarshal(args[0], &call); err == nil {
+			data := call.Input
+			if len(data) == 0 {
+				data = call.Data
+			}
+			if len(data) == 4 && call.To != "" && tokenMetadataSelectors[hexutil.Encode(data)] {
+				return "meta:" + strings.ToLower(call.To) + hexutil.Encode(data), cacheForever
+			}
+		}
+	}
+
+	scope := cacheHead
+	if idx < len(args) {
+		scope = blockScope(args[idx])
+	}
+	if scope == cacheNever {
+		return "", cacheNever
+	}
+	return method + string(params), scope
+}
+
+// blockScope classifies a block parameter: a tag, a number, a hash or an EIP-1898 object.
+func blockScope(arg json.RawMessage) cacheScope {
+	var tag string
+	if err := json.Unmarshal(arg, &tag); err != nil {
+		// EIP-1898 {"blockHash": ...} or {"blockNumber": ...}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(arg, &obj); err != nil {
+			return cacheNever
+		}
+		if n, ok := obj["blockNumber"]; ok {
+			return blockScope(n)
+		}
+		if _, ok := obj["blockHash"]; ok {
+			return cacheForever
+		}
+		return cacheNever
+	}
+	switch tag {
+	case "latest", "safe", "finalized", "":
+		return cacheHead
+	case "pending":
+		return cacheNever
+	case "earliest":
+		return cacheForever
+	}
+	if strings.HasPrefix(tag, "0x") {
+		return cacheForever
+	}
+	return cacheNever
+}
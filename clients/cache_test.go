@@ -0,0 +1,113 @@
+package clients
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// newCachedClient returns a client on fake with the cache on, once the cache tracks heads.
+func newCachedClient(t *testing.T, fake *FakeBackend) *Client {
+	t.Helper()
+	client, err := NewClientFromBackend(fake.On("eth_blockNumber", "0x1"), WithCache(CacheConfig{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		client.cache.mu.Lock()
+		tracking := client.cache.heads
+		client.cache.mu.Unlock()
+		if tracking {
+			return client
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the cache does not track heads")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCacheScopes(t *testing.T) {
+	fake := NewFakeBackend().On("eth_getBalance", "0x64")
+	client := newCachedClient(t, fake)
+	ctx := context.Background()
+	addr := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	// balances reads calls times and returns how many reads reached the node
+	balances := func(read func() (*big.Int, error), calls int) int {
+		t.Helper()
+		before := len(fake.CallsTo("eth_getBalance"))
+		for i := 0; i < calls; i++ {
+			if _, err := read(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return len(fake.CallsTo("eth_getBalance")) - before
+	}
+
+	// A block number is cached for good, "latest" until the next head, "pending" never
+	atBlock := func() (*big.Int, error) { return client.Eth.BalanceAt(ctx, addr, big.NewInt(5)) }
+	atLatest := func() (*big.Int, error) { return client.Eth.BalanceAt(ctx, addr, nil) }
+	if n := balances(atBlock, 2); n != 1 {
+		t.Fatalf("at a block number: %d calls, want 1", n)
+	}
+	if n := balances(atLatest, 2); n != 1 {
+		t.Fatalf("at latest: %d calls, want 1", n)
+	}
+	client.cache.advance()
+	if n := balances(atLatest, 1); n != 1 {
+		t.Fatalf("at latest after a new head: %d calls, want 1", n)
+	}
+	if n := balances(atBlock, 1); n != 0 {
+		t.Fatalf("at a block number after a new head: %d calls, want 0", n)
+	}
+	if n := balances(func() (*big.Int, error) { return client.Eth.PendingBalanceAt(ctx, addr) }, 2); n != 2 {
+		t.Fatalf("at pending: %d calls, want 2", n)
+	}
+}
+
+func TestCacheCountsEveryBatchElement(t *testing.T) {
+	fake := NewFakeBackend().On("eth_getBalance", "0x64")
+	client := newCachedClient(t, fake)
+	ctx := context.Background()
+	a := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	b := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	if _, err := client.Eth.BalanceAt(ctx, a, big.NewInt(5)); err != nil {
+		t.Fatal(err)
+	}
+	batch := func() {
+		t.Helper()
+		elems := []rpc.BatchElem{
+			{Method: "eth_getBalance", Args: []interface{}{a, "0x5"}, Result: new(hexutil.Big)},
+			{Method: "eth_getBalance", Args: []interface{}{b, "0x5"}, Result: new(hexutil.Big)},
+		}
+		if err := client.RpcClient.BatchCallContext(ctx, elems); err != nil {
+			t.Fatal(err)
+		}
+		for _, elem := range elems {
+			if elem.Error != nil {
+				t.Fatal(elem.Error)
+			}
+		}
+	}
+
+	batch() // a hits, b misses: the batch is sent on
+	if got := client.CacheStats(); got.Hits != 1 || got.Misses != 2 {
+		t.Fatalf("stats = %+v, want 1 hit and 2 misses", got)
+	}
+	batch() // both hit: served from the cache
+	if got := client.CacheStats(); got.Hits != 3 || got.Misses != 2 || got.Entries != 2 {
+		t.Fatalf("stats = %+v, want 3 hits, 2 misses and 2 entries", got)
+	}
+	if n := len(fake.CallsTo("eth_getBalance")); n != 3 {
+		t.Fatalf("%d eth_getBalance calls, want 3", n)
+	}
+}
@@ -0,0 +1,77 @@
+package clients
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// CallOption selects the block a read is executed against.
+// Reads without options use the latest block.
+type CallOption func(*callConfig)
+
+type callConfig struct {
+	number *big.Int // block number, or a negative rpc.BlockNumber tag
+	hash   *common.Hash
+}
+
+// AtBlock reads the state at the given block number; nil means latest.
+func AtBlock(number *big.Int) CallOption {
+	return func(cfg *callConfig) {
+		cfg.number, cfg.hash = number, nil
+	}
+}
+
+// AtBlockNumber reads the state at the given block number.
+func AtBlockNumber(number uint64) CallOption {
+	return AtBlock(new(big.Int).SetUint64(number))
+}
+
+// AtBlockHash reads the state at the block with the given hash.
+func AtBlockHash(hash common.Hash) CallOption {
+	return func(cfg *callConfig) {
+		cfg.number, cfg.hash = nil, &hash
+	}
+}
+
+// AtPending reads the pending state, including transactions not yet in a block.
+func AtPending() CallOption {
+	return AtBlock(big.NewInt(int64(rpc.PendingBlockNumber)))
+}
+
+// AtSafe reads the state at the latest safe block.
+func AtSafe() CallOption {
+	return AtBlock(big.NewInt(int64(rpc.SafeBlockNumber)))
+}
+
+// AtFinalized reads the state at the latest finalized block.
+func AtFinalized() CallOption {
+	return AtBlock(big.NewInt(int64(rpc.FinalizedBlockNumber)))
+}
+
+// newCallConfig applies the given options to the latest-block default.
+func newCallConfig(opts []CallOption) *callConfig {
+	cfg := &callConfig{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(cfg)
+		}
+	}
+	return cfg
+}
+
+// blockArg returns the JSON-RPC block parameter: a tag, a hex number or an EIP-1898 hash object.
+func (cfg *callConfig) blockArg() interface{} {
+	switch {
+	case cfg.hash != nil:
+		return rpc.BlockNumberOrHashWithHash(*cfg.hash, false)
+	case cfg.number == nil:
+		return "latest"
+	case cfg.number.Sign() >= 0:
+		return hexutil.EncodeBig(cfg.number)
+	default:
+		return rpc.BlockNumber(cfg.number.Int64()).String()
+	}
+}
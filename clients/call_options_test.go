@@ -0,0 +1,103 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestCallOptionsSelectTheBlock(t *testing.T) {
+	hash := common.HexToHash("0x1111111111111111111111111111111111111111111111111111111111111111")
+	byHash, err := json.Marshal(rpc.BlockNumberOrHashWithHash(hash, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		opts []CallOption
+		want string // JSON block parameter
+	}{
+		{"default", nil, `"latest"`},
+		{"nil option", []CallOption{nil}, `"latest"`},
+		{"nil number", []CallOption{AtBlock(nil)}, `"latest"`},
+		{"number", []CallOption{AtBlockNumber(5)}, `"0x5"`},
+		{"pending", []CallOption{AtPending()}, `"pending"`},
+		{"safe", []CallOption{AtSafe()}, `"safe"`},
+		{"finalized", []CallOption{AtFinalized()}, `"finalized"`},
+		{"hash", []CallOption{AtBlockHash(hash)}, string(byHash)},
+		{"number after hash", []CallOption{AtBlockHash(hash), AtBlockNumber(5)}, `"0x5"`},
+		{"hash after number", []CallOption{AtBlockNumber(5), AtBlockHash(hash)}, string(byHash)},
+	}
+
+	results := map[string]interface{}{
+		"eth_getBalance":          "0x1",
+		"eth_getTransactionCount": "0x1",
+		"eth_call":                hexutil.Bytes(make([]byte, 32)),
+	}
+	fake := NewFakeBackend()
+	client, err := NewClientFromBackend(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	token, err := NewERC20(client, common.HexToAddress("0x00000000000000000000000000000000000000cc"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	addr := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	// Every read takes the block as its second parameter
+	reads := []struct {
+		name   string
+		method string
+		read   func(opts []CallOption) error
+	}{
+		{"BalanceAt", "eth_getBalance", func(opts []CallOption) error {
+			_, err := client.BalanceAt(ctx, addr, opts...)
+			return err
+		}},
+		{"NonceAt", "eth_getTransactionCount", func(opts []CallOption) error {
+			_, err := client.NonceAt(ctx, addr, opts...)
+			return err
+		}},
+		{"CallContract", "eth_call", func(opts []CallOption) error {
+			_, err := client.CallContract(ctx, ethereum.CallMsg{To: &addr}, opts...)
+			return err
+		}},
+		{"ERC20.BalanceOf", "eth_call", func(opts []CallOption) error {
+			_, err := token.BalanceOf(ctx, addr, opts...)
+			return err
+		}},
+		{"Batch.BalanceAt", "eth_getBalance", func(opts []CallOption) error {
+			batch := client.NewBatch()
+			res := batch.BalanceAt(addr, opts...)
+			if err := batch.Execute(ctx); err != nil {
+				return err
+			}
+			return res.Err
+		}},
+	}
+
+	for _, r := range reads {
+		for _, tt := range tests {
+			fake.Reset()
+			fake.On(r.method, results[r.method])
+			if err := r.read(tt.opts); err != nil {
+				t.Fatalf("%s %s: %v", r.name, tt.name, err)
+			}
+			calls := fake.CallsTo(r.method)
+			if len(calls) != 1 || len(calls[0].Params) < 2 {
+				t.Fatalf("%s %s: calls = %+v", r.name, tt.name, calls)
+			}
+			if got := string(calls[0].Params[1]); got != tt.want {
+				t.Errorf("%s %s: block = %s, want %s", r.name, tt.name, got, tt.want)
+			}
+		}
+	}
+}
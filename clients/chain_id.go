@@ -0,0 +1,69 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// ErrChainIDMismatch is returned when the node's chain ID differs from the expected one.
+var ErrChainIDMismatch = errors.New("chain ID mismatch")
+
+// WithExpectedChainID makes dialing fail unless the node reports the given chain ID.
+// The reported ID is cached, not checked again; transactions signed WithChainID for
+// another chain are refused. DialNetwork sets it to the network's chain ID.
+func WithExpectedChainID(chainID uint64) DialOption {
+	return func(cfg *dialConfig) {
+		cfg.expectedChainID = new(big.Int).SetUint64(chainID)
+	}
+}
+
+// chainIDCache holds the chain ID fetched once from the node.
+type chainIDCache struct {
+	expected *big.Int
+
+	mu sync.Mutex
+	id *big.Int
+}
+
+// ChainID returns the chain ID reported by eth_chainId, fetched once and cached.
+// Fails with ErrChainIDMismatch if it differs from the expected chain ID.
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	id, err := c.reportedChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.chainID.expected != nil && id.Cmp(c.chainID.expected) != 0 {
+		return nil, fmt.Errorf("%w: node reports %s, expected %s", ErrChainIDMismatch, id, c.chainID.expected)
+	}
+	return id, nil
+}
+
+// reportedChainID returns a copy of the cached chain ID, fetching it on first use.
+// Unlike ChainID it does not compare it to the expected chain ID.
+func (c *Client) reportedChainID(ctx context.Context) (*big.Int, error) {
+	c.chainID.mu.Lock()
+	defer c.chainID.mu.Unlock()
+
+	if c.chainID.id == nil {
+		id, err := c.Eth.ChainID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.chainID.id = id
+	}
+	return new(big.Int).Set(c.chainID.id), nil
+}
+
+// knownChainID returns the expected chain ID, else the cached one, without calling the node.
+// It is nil if neither is known yet.
+func (c *Client) knownChainID() *big.Int {
+	if c.chainID.expected != nil {
+		return c.chainID.expected
+	}
+	c.chainID.mu.Lock()
+	defer c.chainID.mu.Unlock()
+	return c.chainID.id
+}
@@ -5,111 +5,232 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
+// DefaultPollInterval is how often polled subscriptions query an HTTP endpoint.
+const DefaultPollInterval = time.Second
+
 type Client struct {
 	Eth       *ethclient.Client
 	RpcClient *rpc.Client
-	isWS      bool
+
+	// subRPC carries subscriptions when the transport supports them (WS);
+	// when nil, subscriptions fall back to polling over RpcClient.
+	subRPC       *rpc.Client
+	subEth       *ethclient.Client
+	pollInterval atomic.Int64 // time.Duration
+
+	network     *Network
+	pool        *EndpointPool
+	limiter     *rateLimiter
+	cache       *responseCache
+	inst        Instrumentation
+	logger      *loggerRef
+	chainID     chainIDCache
+	bridges     bridgeCache
+	feeStrategy feeStrategyRef
+	onClose     []func()
 }
 
-// DialHTTP creates a client for HTTP connections (query & tx).
-// Returns a Client instance or an error if the URL is invalid.
-func DialHTTP(url string) (*Client, error) {
-	if !strings.HasPrefix(url, "http") {
-		return nil, fmt.Errorf("DialHTTP requires an http:// or https:// URL")
+// Dial creates a client for any supported URL (http, https, ws, wss or an IPC socket path).
+// Queries, transactions and subscriptions all work; over HTTP subscriptions are polled.
+func Dial(url string, opts ...DialOption) (*Client, error) {
+	cfg := newDialConfig(opts)
+	rpcClient, err := cfg.dialRPC(context.Background(), url)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(url, "http") {
+		return cfg.attach(newClientFromRPC(rpcClient))
 	}
 
-	eth, err := ethclient.Dial(url)
+	c, err := cfg.clientFromRPC(rpcClient)
 	if err != nil {
 		return nil, err
 	}
+	return cfg.attach(c)
+}
 
-	return &Client{
-		Eth:  eth,
-		isWS: false,
-	}, nil
+// DialHTTP creates a client for HTTP connections (query & tx).
+// Subscriptions are supported through a polling fallback.
+func DialHTTP(url string, opts ...DialOption) (*Client, error) {
+	if !strings.HasPrefix(url, "http") {
+		return nil, fmt.Errorf("DialHTTP requires an http:// or https:// URL")
+	}
+
+	return Dial(url, opts...)
 }
 
-// DialWS creates a client for WebSocket connections (subscriptions).
+// DialWS creates a client for WebSocket connections (query, tx & subscriptions).
 // Returns a Client instance or an error if the URL is invalid.
-func DialWS(url string) (*Client, error) {
+func DialWS(url string, opts ...DialOption) (*Client, error) {
 	if !strings.HasPrefix(url, "ws") {
 		return nil, fmt.Errorf("DialWS requires a ws:// or wss:// URL")
 	}
 
-	rpcClient, err := rpc.Dial(url)
+	return Dial(url, opts...)
+}
+
+// DialPaired creates a client that sends calls over HTTP and subscriptions over WebSocket.
+// The middleware options apply to the calls; eth_subscribe requests and the notifications
+// they produce go straight over the WebSocket. Both connections are closed by Close.
+func DialPaired(httpURL, wsURL string, opts ...DialOption) (*Client, error) {
+	if !strings.HasPrefix(httpURL, "http") {
+		return nil, fmt.Errorf("DialPaired requires an http:// or https:// URL for calls")
+	}
+	if !strings.HasPrefix(wsURL, "ws") {
+		return nil, fmt.Errorf("DialPaired requires a ws:// or wss:// URL for subscriptions")
+	}
+
+	cfg := newDialConfig(opts)
+	rpcClient, err := cfg.dialRPC(context.Background(), httpURL)
+	if err != nil {
+		return nil, err
+	}
+	subRPC, err := cfg.dialRPC(context.Background(), wsURL)
+	if err != nil {
+		rpcClient.Close()
+		return nil, err
+	}
+
+	return cfg.attach(newClient(rpcClient, subRPC))
+}
+
+// DialIPC creates a client for a node's IPC endpoint (a Unix socket or Windows named pipe).
+// Like a WebSocket client it supports queries, transactions and native subscriptions.
+func DialIPC(path string, opts ...DialOption) (*Client, error) {
+	if strings.HasPrefix(path, "http") || strings.HasPrefix(path, "ws") {
+		return nil, fmt.Errorf("DialIPC requires a socket path, got URL %q", path)
+	}
+
+	rpcClient, err := rpc.DialIPC(context.Background(), path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := newDialConfig(opts)
+	c, err := cfg.clientFromRPC(rpcClient)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.attach(c)
+}
+
+// NewClientFromRPC wraps an existing connection, such as one from rpc.DialInProc.
+// Subscriptions are native when the connection supports them, polled otherwise.
+// Retry, cache, rate-limit and instrumentation options apply to its calls, but not to
+// TLS, proxy or header options, which are fixed when rpcClient is dialed. Close closes rpcClient.
+func NewClientFromRPC(rpcClient *rpc.Client, opts ...DialOption) (*Client, error) {
+	if rpcClient == nil {
+		return nil, fmt.Errorf("rpc client is nil")
+	}
+	cfg := newDialConfig(opts)
+	c, err := cfg.clientFromRPC(rpcClient)
 	if err != nil {
 		return nil, err
 	}
+	return cfg.attach(c)
+}
+
+// newClientFromRPC wires a Client that subscribes over rpcClient when it can, and polls otherwise.
+func newClientFromRPC(rpcClient *rpc.Client) *Client {
+	if rpcClient.SupportsSubscriptions() {
+		return newClient(rpcClient, rpcClient)
+	}
+	return newClient(rpcClient, nil)
+}
 
-	return &Client{
+// newClient wires a Client around a call connection and an optional subscription connection.
+func newClient(rpcClient, subRPC *rpc.Client) *Client {
+	c := &Client{
 		Eth:       ethclient.NewClient(rpcClient),
 		RpcClient: rpcClient,
-		isWS:      true,
-	}, nil
+		subRPC:    subRPC,
+		logger:    &loggerRef{},
+	}
+	c.pollInterval.Store(int64(DefaultPollInterval))
+	if subRPC != nil {
+		c.subEth = ethclient.NewClient(subRPC)
+	}
+	return c
 }
 
-// Close closes the underlying Ethereum client connection.
+// Close closes the underlying Ethereum client connections.
 // Should be called to release resources.
 func (c *Client) Close() {
+	for _, fn := range c.onClose {
+		fn()
+	}
 	c.Eth.Close()
+	if c.subRPC != nil && c.subRPC != c.RpcClient {
+		c.subRPC.Close()
+	}
 }
 
 // BalanceAt queries the balance of an address.
-// Only supported on HTTP connections; returns an error for WebSocket.
-func (c *Client) BalanceAt(ctx context.Context, addr common.Address) (*big.Int, error) {
-	if c.isWS {
-		return nil, fmt.Errorf("BalanceAt requires an HTTP connection, not WebSocket")
+// Returns the balance in wei at the latest block, or at the block selected by opts.
+func (c *Client) BalanceAt(ctx context.Context, addr common.Address, opts ...CallOption) (*big.Int, error) {
+	cfg := newCallConfig(opts)
+	if cfg.hash != nil {
+		return c.Eth.BalanceAtHash(ctx, addr, *cfg.hash)
 	}
-
-	return c.Eth.BalanceAt(ctx, addr, nil)
+	return c.Eth.BalanceAt(ctx, addr, cfg.number)
 }
 
 // NonceAt queries the account nonce for an address.
-// Only supported on HTTP connections; returns an error for WebSocket.
-func (c *Client) NonceAt(ctx context.Context, addr common.Address) (uint64, error) {
-	if c.isWS {
-		return 0, fmt.Errorf("NonceAt requires an HTTP connection, not WebSocket")
+// Returns the nonce at the latest block, or at the block selected by opts.
+func (c *Client) NonceAt(ctx context.Context, addr common.Address, opts ...CallOption) (uint64, error) {
+	cfg := newCallConfig(opts)
+	if cfg.hash != nil {
+		return c.Eth.NonceAtHash(ctx, addr, *cfg.hash)
 	}
-
-	return c.Eth.NonceAt(ctx, addr, nil)
+	return c.Eth.NonceAt(ctx, addr, cfg.number)
 }
 
 // GasPrice returns the current gas price from the network.
-// Only supported on HTTP connections; returns an error for WebSocket.
+// Uses the node's eth_gasPrice suggestion.
 func (c *Client) GasPrice(ctx context.Context) (*big.Int, error) {
-	if c.isWS {
-		return nil, fmt.Errorf("GasPrice requires an HTTP connection, not WebSocket")
-	}
-
 	return c.Eth.SuggestGasPrice(ctx)
 }
 
 // CallContract performs a read-only contract call.
-// Only supported on HTTP connections; returns an error for WebSocket.
-func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
-	if c.isWS {
-		return nil, fmt.Errorf("CallContract requires an HTTP connection, not WebSocket")
+// Executes against the latest block, or the block selected by opts, without creating a transaction.
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, opts ...CallOption) ([]byte, error) {
+	cfg := newCallConfig(opts)
+	if cfg.hash != nil {
+		return c.Eth.CallContractAtHash(ctx, msg, *cfg.hash)
 	}
-
-	return c.Eth.CallContract(ctx, msg, nil)
+	return c.Eth.CallContract(ctx, msg, cfg.number)
 }
 
 // SendTransaction sends a signed transaction to the network.
-// Only supported on HTTP connections; returns an error for WebSocket.
+// Returns an error if the node rejects the transaction.
 func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
-	if c.isWS {
-		return fmt.Errorf("SendTransaction requires an HTTP connection, not WebSocket")
-	}
+	ctx, end := c.instrumentation().StartTxSend(ctx, tx)
+	err := c.Eth.SendTransaction(ctx, tx)
+	end(err)
+	return err
+}
 
-	return c.Eth.SendTransaction(ctx, tx)
+// SendTransaction712 sends a signed EIP-712 transaction to the network.
+// Returns an error if the node rejects the transaction.
+func (c *Client) SendTransaction712(ctx context.Context, tx *Transaction712) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	ctx, end := c.instrumentation().StartTxSend(ctx, tx)
+	err = c.RpcClient.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(raw))
+	end(err)
+	return err
 }
 
 // EstimateGasWithBuffer estimates gas for a CallMsg and applies a buffer percentage.
@@ -126,3 +247,55 @@ func (c *Client) EstimateGasWithBuffer(ctx context.Context, msg ethereum.CallMsg
 
 	return gasWithBuffer, nil
 }
+
+// EstimateGas712 estimates gas for a CallMsg sent as an EIP-712 transaction.
+// The zkSync meta (factory deps, paymaster) is attached since it changes the estimate.
+func (c *Client) EstimateGas712(ctx context.Context, msg ethereum.CallMsg, meta *EIP712Meta) (uint64, error) {
+	if meta == nil {
+		meta = &EIP712Meta{}
+	}
+
+	var gas hexutil.Uint64
+	if err := c.RpcClient.CallContext(ctx, &gas, "eth_estimateGas", toCallArg(msg, meta)); err != nil {
+		return 0, err
+	}
+	return uint64(gas), nil
+}
+
+// encodeEIP712Meta converts an EIP712Meta to the JSON form expected by zkSync RPC.
+// Byte fields are sent as arrays of numbers, as the node requires.
+func encodeEIP712Meta(meta *EIP712Meta) map[string]interface{} {
+	gasPerPubdata := meta.GasPerPubdata
+	if gasPerPubdata == nil {
+		gasPerPubdata = big.NewInt(DefaultGasPerPubdata)
+	}
+	out := map[string]interface{}{
+		"gasPerPubdata": (*hexutil.Big)(gasPerPubdata),
+	}
+	if len(meta.FactoryDeps) > 0 {
+		deps := make([][]int, len(meta.FactoryDeps))
+		for i, dep := range meta.FactoryDeps {
+			deps[i] = byteArray(dep)
+		}
+		out["factoryDeps"] = deps
+	}
+	if len(meta.CustomSignature) > 0 {
+		out["customSignature"] = byteArray(meta.CustomSignature)
+	}
+	if pp := meta.PaymasterParams; pp != nil {
+		out["paymasterParams"] = map[string]interface{}{
+			"paymaster":      pp.Paymaster,
+			"paymasterInput": byteArray(pp.PaymasterInput),
+		}
+	}
+	return out
+}
+
+// byteArray converts b to a slice of ints so it marshals as a JSON number array.
+func byteArray(b []byte) []int {
+	out := make([]int, len(b))
+	for i, v := range b {
+		out[i] = int(v)
+	}
+	return out
+}
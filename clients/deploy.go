@@ -0,0 +1,269 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ContractDeployerAddress is the ZK Stack system contract that performs deployments.
+	ContractDeployerAddress = common.HexToAddress("0x0000000000000000000000000000000000008006")
+
+	// NonceHolderAddress is the ZK Stack system contract that tracks account nonces.
+	NonceHolderAddress = common.HexToAddress("0x0000000000000000000000000000000000008003")
+)
+
+const contractDeployerABI = `[
+	{"inputs":[{"name":"_salt","type":"bytes32"},{"name":"_bytecodeHash","type":"bytes32"},{"name":"_input","type":"bytes"}],"name":"create","outputs":[{"name":"","type":"address"}],"stateMutability":"payable","type":"function"},
+	{"inputs":[{"name":"_salt","type":"bytes32"},{"name":"_bytecodeHash","type":"bytes32"},{"name":"_input","type":"bytes"}],"name":"create2","outputs":[{"name":"","type":"address"}],"stateMutability":"payable","type":"function"}
+]`
+
+const nonceHolderABI = `[
+	{"inputs":[{"name":"_address","type":"address"}],"name":"getDeploymentNonce","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"}
+]`
+
+var (
+	contractDeployer = mustParseABI(contractDeployerABI)
+	nonceHolder      = mustParseABI(nonceHolderABI)
+)
+
+// ContractArtifact is a compiled zksolc contract ready for deployment.
+// FactoryDeps holds the bytecodes of contracts this one deploys at runtime.
+type ContractArtifact struct {
+	ContractName     string
+	ABI              abi.ABI
+	Bytecode         []byte
+	FactoryDeps      [][]byte
+	FactoryDepHashes []common.Hash
+}
+
+// artifactJSON covers both the hardhat-zksync and foundry-zksync artifact layouts.
+type artifactJSON struct {
+	ContractName        string            `json:"contractName"`
+	ABI                 json.RawMessage   `json:"abi"`
+	Bytecode            json.RawMessage   `json:"bytecode"`
+	FactoryDeps         map[string]string `json:"factoryDeps"`
+	FactoryDependencies map[string]string `json:"factoryDependencies"`
+}
+
+// LoadArtifact parses a zksolc artifact JSON (hardhat-zksync or foundry-zksync).
+// Bytecodes of listed factory deps must be added with AddFactoryDep before deploying.
+func LoadArtifact(data []byte) (*ContractArtifact, error) {
+	var raw artifactJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw.ABI) == 0 {
+		return nil, errors.New("artifact has no abi")
+	}
+	parsedABI, err := abi.JSON(strings.NewReader(string(raw.ABI)))
+	if err != nil {
+		return nil, err
+	}
+
+	// hardhat stores "0x..." directly, foundry nests it as {"object": "..."}
+	var code string
+	if err := json.Unmarshal(raw.Bytecode, &code); err != nil {
+		var nested struct {
+			Object string `json:"object"`
+		}
+		if err := json.Unmarshal(raw.Bytecode, &nested); err != nil {
+			return nil, errors.New("artifact bytecode has an unknown format")
+		}
+		code = nested.Object
+	}
+	if !strings.HasPrefix(code, "0x") {
+		code = "0x" + code
+	}
+	bytecode, err := hexutil.Decode(code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact bytecode: %w", err)
+	}
+
+	artifact := &ContractArtifact{
+		ContractName: raw.ContractName,
+		ABI:          parsedABI,
+		Bytecode:     bytecode,
+	}
+	deps := raw.FactoryDeps
+	if len(deps) == 0 {
+		deps = raw.FactoryDependencies
+	}
+	for hash := range deps {
+		artifact.FactoryDepHashes = append(artifact.FactoryDepHashes, common.HexToHash(hash))
+	}
+	return artifact, nil
+}
+
+// LoadArtifactFile reads and parses a zksolc artifact JSON file.
+func LoadArtifactFile(path string) (*ContractArtifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadArtifact(data)
+}
+
+// AddFactoryDep attaches a dependency's bytecode, and its own deps, to the artifact.
+func (a *ContractArtifact) AddFactoryDep(dep *ContractArtifact) {
+	a.FactoryDeps = append(a.FactoryDeps, dep.Bytecode)
+	a.FactoryDeps = append(a.FactoryDeps, dep.FactoryDeps...)
+}
+
+// BytecodeHash returns the versioned zkSync hash of the artifact bytecode.
+func (a *ContractArtifact) BytecodeHash() (common.Hash, error) {
+	return HashBytecode(a.Bytecode)
+}
+
+// DeployContract deploys an artifact through the ContractDeployer `create` method.
+// Returns the deployed address, derived from the sender's deployment nonce at the pending
+// block, and the transaction. If another deployment from the same account lands first
+// (e.g. one sent concurrently), the address is wrong; DeployedAddress reads the actual
+// address from the receipt.
+func (w *Wallet) DeployContract(ctx context.Context, client *Client, artifact *ContractArtifact, nm *NonceManager, args []interface{}, opts ...TxOption) (common.Address, *Transaction712, error) {
+	var zero common.Address
+	if artifact == nil {
+		return zero, nil, errNilArtifact
+	}
+
+	msg := ethereum.CallMsg{To: &NonceHolderAddress}
+	msg.Data, _ = nonceHolder.Pack("getDeploymentNonce", w.Address)
+	res, err := client.CallContract(ctx, msg, AtPending())
+	if err != nil {
+		return zero, nil, err
+	}
+	deploymentNonce := new(big.Int)
+	if err := nonceHolder.UnpackIntoInterface(&deploymentNonce, "getDeploymentNonce", res); err != nil {
+		return zero, nil, err
+	}
+
+	tx, err := w.deploy(ctx, client, artifact, "create", common.Hash{}, nm, args, opts)
+	if err != nil {
+		return zero, nil, err
+	}
+	return CreateAddress(w.Address, deploymentNonce), tx, nil
+}
+
+// DeployContractCreate2 deploys an artifact through the ContractDeployer `create2` method.
+// The address depends only on the sender, salt, bytecode and constructor input.
+func (w *Wallet) DeployContractCreate2(ctx context.Context, client *Client, artifact *ContractArtifact, salt common.Hash, nm *NonceManager, args []interface{}, opts ...TxOption) (common.Address, *Transaction712, error) {
+	var zero common.Address
+	if artifact == nil {
+		return zero, nil, errNilArtifact
+	}
+
+	bytecodeHash, err := artifact.BytecodeHash()
+	if err != nil {
+		return zero, nil, err
+	}
+	input, err := artifact.ABI.Pack("", args...)
+	if err != nil {
+		return zero, nil, err
+	}
+
+	tx, err := w.deploy(ctx, client, artifact, "create2", salt, nm, args, opts)
+	if err != nil {
+		return zero, nil, err
+	}
+	return Create2Address(w.Address, bytecodeHash, salt, input), tx, nil
+}
+
+var errNilArtifact = errors.New("artifact is nil")
+
+// contractDeployedTopic is the signature of the ContractDeployer's
+// ContractDeployed(address indexed deployer, bytes32 indexed bytecodeHash, address indexed contractAddress) event.
+var contractDeployedTopic = crypto.Keccak256Hash([]byte("ContractDeployed(address,bytes32,address)"))
+
+// DeployedAddress returns the address of the contract deployer created, from the
+// ContractDeployed event in the deployment's receipt. Contracts deployed by its
+// constructor are skipped, since their deployer is the new contract.
+func DeployedAddress(receipt *types.Receipt, deployer common.Address) (common.Address, error) {
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return common.Address{}, fmt.Errorf("deployment %s failed", receipt.TxHash.Hex())
+	}
+	deployerTopic := common.BytesToHash(deployer.Bytes())
+	for _, l := range receipt.Logs {
+		if l.Address == ContractDeployerAddress && len(l.Topics) == 4 &&
+			l.Topics[0] == contractDeployedTopic && l.Topics[1] == deployerTopic {
+			return common.BytesToAddress(l.Topics[3].Bytes()), nil
+		}
+	}
+	return common.Address{}, fmt.Errorf("no ContractDeployed event in receipt of %s", receipt.TxHash.Hex())
+}
+
+// deploy sends an EIP-712 call to the ContractDeployer with the artifact as a factory dep.
+func (w *Wallet) deploy(ctx context.Context, client *Client, artifact *ContractArtifact, method string, salt common.Hash, nm *NonceManager, args []interface{}, opts []TxOption) (*Transaction712, error) {
+	if artifact == nil {
+		return nil, errNilArtifact
+	}
+	bytecodeHash, err := artifact.BytecodeHash()
+	if err != nil {
+		return nil, err
+	}
+
+	// Every dependency the compiler listed must be shipped with the deployment
+	provided := make(map[common.Hash]bool, len(artifact.FactoryDeps))
+	for _, dep := range artifact.FactoryDeps {
+		h, err := HashBytecode(dep)
+		if err != nil {
+			return nil, err
+		}
+		provided[h] = true
+	}
+	for _, h := range artifact.FactoryDepHashes {
+		if !provided[h] {
+			return nil, fmt.Errorf("missing factory dependency %s", h.Hex())
+		}
+	}
+
+	input, err := artifact.ABI.Pack("", args...)
+	if err != nil {
+		return nil, err
+	}
+	data, err := contractDeployer.Pack(method, salt, bytecodeHash, input)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &EIP712Meta{
+		FactoryDeps: append([][]byte{artifact.Bytecode}, artifact.FactoryDeps...),
+	}
+	return w.BuildAndSendTx712(ctx, client, &ContractDeployerAddress, big.NewInt(0), data, nm, meta, opts...)
+}
+
+// CreateAddress computes the address of a contract deployed with zkSync CREATE.
+// nonce is the sender's deployment nonce, not its transaction nonce.
+func CreateAddress(sender common.Address, nonce *big.Int) common.Address {
+	hash := crypto.Keccak256(
+		crypto.Keccak256([]byte("zksyncCreate")),
+		common.LeftPadBytes(sender.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(nonce)),
+	)
+	return common.BytesToAddress(hash[12:])
+}
+
+// Create2Address computes the address of a contract deployed with zkSync CREATE2.
+// bytecodeHash is the versioned hash returned by HashBytecode.
+func Create2Address(sender common.Address, bytecodeHash, salt common.Hash, input []byte) common.Address {
+	hash := crypto.Keccak256(
+		crypto.Keccak256([]byte("zksyncCreate2")),
+		common.LeftPadBytes(sender.Bytes(), 32),
+		salt.Bytes(),
+		bytecodeHash.Bytes(),
+		crypto.Keccak256(input),
+	)
+	return common.BytesToAddress(hash[12:])
+}
@@ -0,0 +1,75 @@
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func contractDeployedLog(deployer, contract common.Address) *types.Log {
+	return &types.Log{
+		Address: ContractDeployerAddress,
+		Topics: []common.Hash{
+			contractDeployedTopic,
+			common.BytesToHash(deployer.Bytes()),
+			common.HexToHash("0x01"),
+			common.BytesToHash(contract.Bytes()),
+		},
+	}
+}
+
+func TestDeployedAddressSkipsNestedDeployments(t *testing.T) {
+	wallet := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	outer := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	inner := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+
+	// The constructor's deployment is emitted before the outer one
+	receipt := &types.Receipt{
+		Status: types.ReceiptStatusSuccessful,
+		Logs:   []*types.Log{contractDeployedLog(outer, inner), contractDeployedLog(wallet, outer)},
+	}
+	got, err := DeployedAddress(receipt, wallet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != outer {
+		t.Fatalf("address = %s, want %s", got, outer)
+	}
+}
+
+func TestDeployedAddressFailsWithoutEvent(t *testing.T) {
+	wallet := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	for _, receipt := range []*types.Receipt{
+		{Status: types.ReceiptStatusFailed},
+		{Status: types.ReceiptStatusSuccessful},
+	} {
+		if _, err := DeployedAddress(receipt, wallet); err == nil {
+			t.Fatalf("receipt %+v: want an error", receipt)
+		}
+	}
+}
+
+func TestDeployRejectsNilArtifact(t *testing.T) {
+	fake := NewFakeBackend()
+	client, err := NewClientFromBackend(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := w.DeployContract(context.Background(), client, nil, nil, nil); err == nil {
+		t.Fatal("DeployContract: want an error")
+	}
+	if _, _, err := w.DeployContractCreate2(context.Background(), client, nil, common.Hash{}, nil, nil); err == nil {
+		t.Fatal("DeployContractCreate2: want an error")
+	}
+	if n := len(fake.Calls()); n != 0 {
+		t.Fatalf("%d calls, want none", n)
+	}
+}
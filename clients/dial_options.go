@@ -0,0 +1,285 @@
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+// DialOption customizes how a Client connects to its endpoints.
+type DialOption func(*dialConfig)
+
+type dialConfig struct {
+	retry       *RetryPolicy
+	rateLimit   *RateLimitConfig
+	cacheConfig *CacheConfig
+	inst        Instrumentation
+	logger      *loggerRef
+
+	expectedChainID *big.Int
+	feeStrategy     FeeStrategy
+
+	headers     http.Header
+	auth        HeaderFunc
+	token       *tokenCache
+	httpClient  *http.Client
+	tlsConfig   *tls.Config
+	proxy       func(*http.Request) (*url.URL, error)
+	callTimeout time.Duration
+	recorder    *Recorder
+
+	limiter   *rateLimiter
+	cache     *responseCache
+	cacheUsed bool // whether a transport was wrapped with the cache
+}
+
+// WithRetryPolicy retries failed RPC calls according to the given policy.
+// Applies to calls on every transport; see RetryPolicy for which calls are retried.
+func WithRetryPolicy(policy RetryPolicy) DialOption {
+	return func(cfg *dialConfig) {
+		cfg.retry = &policy
+	}
+}
+
+// WithHTTPClient sends HTTP requests with client. Its transport is wrapped by the
+// client's middleware (retries, rate limits, headers); its Jar and redirect policy are kept.
+func WithHTTPClient(client *http.Client) DialOption {
+	return func(cfg *dialConfig) {
+		cfg.httpClient = client
+	}
+}
+
+// WithTLSConfig uses tlsConfig for HTTPS and WSS connections, e.g. for private CAs or client certificates.
+// Ignored for a WithHTTPClient client whose transport is not an *http.Transport.
+func WithTLSConfig(tlsConfig *tls.Config) DialOption {
+	return func(cfg *dialConfig) {
+		cfg.tlsConfig = tlsConfig
+	}
+}
+
+// WithProxy routes HTTP and WebSocket connections through the proxy chosen by proxy,
+// e.g. http.ProxyURL(u). Defaults to http.ProxyFromEnvironment.
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) DialOption {
+	return func(cfg *dialConfig) {
+		cfg.proxy = proxy
+	}
+}
+
+// WithCallTimeout bounds each HTTP call, including its retries, to d.
+// Over WebSocket it bounds the connection handshake; use context deadlines for calls.
+// It also replaces DefaultDialTimeout for the checks made while dialing.
+func WithCallTimeout(d time.Duration) DialOption {
+	return func(cfg *dialConfig) {
+		cfg.callTimeout = d
+	}
+}
+
+// DefaultDialTimeout bounds the calls made while dialing, such as the chain ID check,
+// unless WithCallTimeout is set.
+const DefaultDialTimeout = 30 * time.Second
+
+// dialTimeout returns how long a call made while dialing may take.
+func (cfg *dialConfig) dialTimeout() time.Duration {
+	if cfg.callTimeout > 0 {
+		return cfg.callTimeout
+	}
+	return DefaultDialTimeout
+}
+
+// newDialConfig applies the given options to an empty dialConfig.
+func newDialConfig(opts []DialOption) *dialConfig {
+	cfg := &dialConfig{logger: &loggerRef{}}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(cfg)
+		}
+	}
+	if cfg.rateLimit != nil {
+		cfg.limiter = newRateLimiter(*cfg.rateLimit)
+	}
+	if cfg.cacheConfig != nil {
+		cfg.cache = newResponseCache(*cfg.cacheConfig)
+	}
+	return cfg
+}
+
+// attach hands the client the shared state created by the options, verifies the
+// chain ID if one is expected and starts the background work the options need.
+// The client is closed if verification fails.
+func (cfg *dialConfig) attach(c *Client) (*Client, error) {
+	c.limiter = cfg.limiter
+	c.inst = cfg.inst
+	c.logger = cfg.logger
+	c.chainID.expected = cfg.expectedChainID
+	c.feeStrategy.set(cfg.feeStrategy)
+	if cfg.expectedChainID != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.dialTimeout())
+		_, err := c.ChainID(ctx)
+		cancel()
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	if cfg.cache != nil && cfg.cacheUsed {
+		c.cache = cfg.cache
+		ctx, cancel := context.WithCancel(context.Background())
+		go c.cache.watchHeads(ctx, c)
+		c.onClose = append(c.onClose, cancel)
+	}
+	return c, nil
+}
+
+// transport wraps base with the client-wide middleware, outermost first.
+// base is either a single endpoint transport or an EndpointPool.
+func (cfg *dialConfig) transport(base http.RoundTripper) http.RoundTripper {
+	rt := base
+	if cfg.retry != nil {
+		rt = newRetryTransport(rt, *cfg.retry, cfg.logger)
+	}
+	if cfg.cache != nil {
+		rt = cfg.cache.transport(rt)
+		cfg.cacheUsed = true
+	}
+	if cfg.inst != nil {
+		rt = instrumentTransport(rt, cfg.inst)
+	}
+	return rt
+}
+
+// endpointTransport wraps the transport that reaches one endpoint with per-endpoint middleware.
+func (cfg *dialConfig) endpointTransport(base http.RoundTripper) http.RoundTripper {
+	rt := base
+	if cfg.recorder != nil {
+		rt = cfg.recorder.transport(rt)
+	}
+	if cfg.headers != nil || cfg.auth != nil || cfg.token != nil {
+		rt = cfg.headerTransport(rt)
+	}
+	if cfg.limiter != nil {
+		rt = cfg.limiter.transport(rt)
+	}
+	return rt
+}
+
+// baseTransport returns the transport that opens connections, with TLS and proxy settings applied.
+func (cfg *dialConfig) baseTransport() http.RoundTripper {
+	base := http.DefaultTransport
+	if cfg.httpClient != nil && cfg.httpClient.Transport != nil {
+		base = cfg.httpClient.Transport
+	}
+	if cfg.tlsConfig == nil && cfg.proxy == nil {
+		return base
+	}
+	t, ok := base.(*http.Transport)
+	if !ok {
+		return base
+	}
+	t = t.Clone()
+	if cfg.tlsConfig != nil {
+		t.TLSClientConfig = cfg.tlsConfig
+	}
+	if cfg.proxy != nil {
+		t.Proxy = cfg.proxy
+	}
+	return t
+}
+
+// newHTTPClient returns the http.Client that sends requests through rt.
+func (cfg *dialConfig) newHTTPClient(rt http.RoundTripper) *http.Client {
+	c := &http.Client{}
+	if cfg.httpClient != nil {
+		*c = *cfg.httpClient
+	}
+	c.Transport = rt
+	if cfg.callTimeout > 0 {
+		c.Timeout = cfg.callTimeout
+	}
+	return c
+}
+
+// wsOptions returns the rpc options that carry headers, credentials, TLS, proxy and
+// timeout settings to a WebSocket connection.
+func (cfg *dialConfig) wsOptions() []rpc.ClientOption {
+	var opts []rpc.ClientOption
+	if cfg.headers != nil {
+		opts = append(opts, rpc.WithHeaders(cfg.headers))
+	}
+	switch {
+	case cfg.token != nil:
+		opts = append(opts, rpc.WithHTTPAuth(cfg.token.header))
+	case cfg.auth != nil:
+		opts = append(opts, rpc.WithHTTPAuth(rpc.HTTPAuth(cfg.auth)))
+	}
+	if cfg.tlsConfig != nil || cfg.proxy != nil || cfg.callTimeout > 0 {
+		dialer := websocket.Dialer{
+			ReadBufferSize:   1024,
+			WriteBufferSize:  1024,
+			Proxy:            http.ProxyFromEnvironment,
+			TLSClientConfig:  cfg.tlsConfig,
+			HandshakeTimeout: cfg.callTimeout,
+		}
+		if cfg.proxy != nil {
+			dialer.Proxy = cfg.proxy
+		}
+		opts = append(opts, rpc.WithWebsocketDialer(dialer))
+	}
+	return opts
+}
+
+// dialRPC connects to url, routing HTTP traffic through the configured middleware.
+func (cfg *dialConfig) dialRPC(ctx context.Context, url string) (*rpc.Client, error) {
+	var opts []rpc.ClientOption
+	if strings.HasPrefix(url, "http") {
+		transport := cfg.transport(cfg.endpointTransport(cfg.baseTransport()))
+		opts = append(opts, rpc.WithHTTPClient(cfg.newHTTPClient(transport)))
+	} else {
+		opts = cfg.wsOptions()
+	}
+	return rpc.DialOptions(ctx, url, opts...)
+}
+
+// callRPC returns the connection to send calls over for rpcClient, a WebSocket, IPC or
+// in-process connection that has no HTTP transport to wrap. When retry, cache, rate-limit,
+// instrumentation or recorder options are set, calls pass through that middleware in
+// process before reaching rpcClient; otherwise rpcClient is returned as is.
+func (cfg *dialConfig) callRPC(rpcClient *rpc.Client) (*rpc.Client, error) {
+	if cfg.retry == nil && cfg.limiter == nil && cfg.cache == nil && cfg.inst == nil && cfg.recorder == nil {
+		return rpcClient, nil
+	}
+	rt := backendTransport(rpcClient)
+	if cfg.recorder != nil {
+		rt = cfg.recorder.transport(rt)
+	}
+	if cfg.limiter != nil {
+		rt = cfg.limiter.transport(rt)
+	}
+	return rpc.DialOptions(context.Background(), backendURL, rpc.WithHTTPClient(&http.Client{Transport: cfg.transport(rt)}))
+}
+
+// clientFromRPC wires a Client around a connection that is not dialed over HTTP. Calls go
+// through the middleware, see callRPC; subscriptions stay native when rpcClient supports
+// them. rpcClient is closed if the client cannot be created.
+func (cfg *dialConfig) clientFromRPC(rpcClient *rpc.Client) (*Client, error) {
+	calls, err := cfg.callRPC(rpcClient)
+	if err != nil {
+		rpcClient.Close()
+		return nil, err
+	}
+	if calls == rpcClient {
+		return newClientFromRPC(rpcClient), nil
+	}
+	if !rpcClient.SupportsSubscriptions() {
+		c := newClient(calls, nil)
+		c.onClose = append(c.onClose, rpcClient.Close)
+		return c, nil
+	}
+	return newClient(calls, rpcClient), nil
+}
@@ -0,0 +1,101 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// chainIDService serves eth_chainId and counts the calls that reach it.
+type chainIDService struct {
+	calls atomic.Int32
+}
+
+func (s *chainIDService) ChainId() *hexutil.Big {
+	s.calls.Add(1)
+	return (*hexutil.Big)(big.NewInt(2741))
+}
+
+// methodRecorder is an Instrumentation that records the methods of observed calls.
+type methodRecorder struct {
+	nopInstrumentation
+	mu      sync.Mutex
+	methods []string
+}
+
+func (r *methodRecorder) StartRPC(ctx context.Context, method string) (context.Context, func(error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.methods = append(r.methods, method)
+	return ctx, func(error) {}
+}
+
+func TestMiddlewareAppliesToInProcConnections(t *testing.T) {
+	svc := &chainIDService{}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", svc); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	rec := &methodRecorder{}
+	client, err := NewClientFromRPC(rpc.DialInProc(server),
+		WithCache(CacheConfig{}), WithInstrumentation(rec), WithRateLimit(RateLimitConfig{PerEndpoint: RateLimit{Rate: 1000, Burst: 10}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if client.subRPC == nil {
+		t.Fatal("in-process subscriptions should stay native")
+	}
+
+	for i := 0; i < 3; i++ {
+		id, err := client.Eth.ChainID(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id.Int64() != 2741 {
+			t.Fatalf("chain ID = %s, want 2741", id)
+		}
+	}
+	if n := svc.calls.Load(); n != 1 {
+		t.Fatalf("node served %d eth_chainId calls, want 1 (cached)", n)
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.methods) != 3 || rec.methods[0] != "eth_chainId" {
+		t.Fatalf("observed calls %v, want 3 eth_chainId", rec.methods)
+	}
+}
+
+// stalledService never answers eth_chainId.
+type stalledService struct{}
+
+func (stalledService) ChainId(ctx context.Context) (*hexutil.Big, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestChainIDCheckIsBoundedByCallTimeout(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", stalledService{}); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	start := time.Now()
+	_, err := NewClientFromRPC(rpc.DialInProc(server), WithExpectedChainID(2741), WithCallTimeout(50*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("dial took %s", elapsed)
+	}
+}
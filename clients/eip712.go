@@ -0,0 +1,369 @@
+package clients
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// EIP712TxType is the EIP-2718 type byte of native zkSync transactions.
+	EIP712TxType = 0x71
+
+	// DefaultGasPerPubdata is the default gasPerPubdata limit used by the ZK Stack.
+	DefaultGasPerPubdata = 50000
+)
+
+var (
+	eip712DomainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version,uint256 chainId)"))
+	eip712TxTypeHash     = crypto.Keccak256Hash([]byte("Transaction(uint256 txType,uint256 from,uint256 to,uint256 gasLimit,uint256 gasPerPubdataByteLimit,uint256 maxFeePerGas,uint256 maxPriorityFeePerGas,uint256 paymaster,uint256 nonce,uint256 value,bytes data,bytes32[] factoryDeps,bytes paymasterInput)"))
+)
+
+// PaymasterParams selects a paymaster and the input passed to it.
+type PaymasterParams struct {
+	Paymaster      common.Address
+	PaymasterInput []byte
+}
+
+// EIP712Meta holds the zkSync-specific fields of an EIP-712 transaction.
+// A nil GasPerPubdata defaults to DefaultGasPerPubdata.
+type EIP712Meta struct {
+	GasPerPubdata   *big.Int
+	FactoryDeps     [][]byte
+	CustomSignature []byte
+	PaymasterParams *PaymasterParams
+}
+
+// EIP712Tx is the data of a native zkSync EIP-712 (type 0x71) transaction.
+// Wrap it with NewTransaction712 to hash, sign and serialize it.
+type EIP712Tx struct {
+	ChainID   *big.Int
+	Nonce     uint64
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+	Gas       uint64
+	To        *common.Address
+	Value     *big.Int
+	Data      []byte
+	From      common.Address
+	Meta      EIP712Meta
+}
+
+// Transaction712 is an immutable zkSync EIP-712 transaction.
+// It mirrors the accessor style of go-ethereum's types.Transaction.
+type Transaction712 struct {
+	inner EIP712Tx
+}
+
+// tx712RLP is the wire layout of a type 0x71 transaction payload.
+// V, R and S are kept for compatibility; the signature travels in CustomSignature.
+type tx712RLP struct {
+	Nonce           uint64
+	GasTipCap       *big.Int
+	GasFeeCap       *big.Int
+	Gas             uint64
+	To              []byte
+	Value           *big.Int
+	Data            []byte
+	V               *big.Int
+	R               []byte
+	S               []byte
+	ChainID         *big.Int
+	From            common.Address
+	GasPerPubdata   *big.Int
+	FactoryDeps     [][]byte
+	CustomSignature []byte
+	PaymasterParams [][]byte
+}
+
+// NewTransaction712 creates an EIP-712 transaction from the given data.
+// Nil numeric fields are treated as zero; the data is copied.
+func NewTransaction712(inner *EIP712Tx) *Transaction712 {
+	cpy := *inner
+	cpy.ChainID = bigOrZero(inner.ChainID)
+	cpy.GasTipCap = bigOrZero(inner.GasTipCap)
+	cpy.GasFeeCap = bigOrZero(inner.GasFeeCap)
+	cpy.Value = bigOrZero(inner.Value)
+	cpy.Data = common.CopyBytes(inner.Data)
+	if inner.To != nil {
+		to := *inner.To
+		cpy.To = &to
+	}
+	if inner.Meta.GasPerPubdata == nil {
+		cpy.Meta.GasPerPubdata = big.NewInt(DefaultGasPerPubdata)
+	} else {
+		cpy.Meta.GasPerPubdata = new(big.Int).Set(inner.Meta.GasPerPubdata)
+	}
+	cpy.Meta.FactoryDeps = make([][]byte, len(inner.Meta.FactoryDeps))
+	for i, dep := range inner.Meta.FactoryDeps {
+		cpy.Meta.FactoryDeps[i] = common.CopyBytes(dep)
+	}
+	cpy.Meta.CustomSignature = common.CopyBytes(inner.Meta.CustomSignature)
+	if inner.Meta.PaymasterParams != nil {
+		cpy.Meta.PaymasterParams = &PaymasterParams{
+			Paymaster:      inner.Meta.PaymasterParams.Paymaster,
+			PaymasterInput: common.CopyBytes(inner.Meta.PaymasterParams.PaymasterInput),
+		}
+	}
+	return &Transaction712{inner: cpy}
+}
+
+// Type returns the transaction type (always EIP712TxType).
+func (tx *Transaction712) Type() uint8 { return EIP712TxType }
+
+// ChainId returns the chain ID the transaction is signed for.
+func (tx *Transaction712) ChainId() *big.Int { return new(big.Int).Set(tx.inner.ChainID) }
+
+// Nonce returns the sender account nonce of the transaction.
+func (tx *Transaction712) Nonce() uint64 { return tx.inner.Nonce }
+
+// Gas returns the gas limit of the transaction.
+func (tx *Transaction712) Gas() uint64 { return tx.inner.Gas }
+
+// GasTipCap returns the maxPriorityFeePerGas of the transaction.
+func (tx *Transaction712) GasTipCap() *big.Int { return new(big.Int).Set(tx.inner.GasTipCap) }
+
+// GasFeeCap returns the maxFeePerGas of the transaction.
+func (tx *Transaction712) GasFeeCap() *big.Int { return new(big.Int).Set(tx.inner.GasFeeCap) }
+
+// Value returns the ether amount of the transaction.
+func (tx *Transaction712) Value() *big.Int { return new(big.Int).Set(tx.inner.Value) }
+
+// Data returns the input data of the transaction.
+func (tx *Transaction712) Data() []byte { return common.CopyBytes(tx.inner.Data) }
+
+// From returns the account the transaction is sent from.
+func (tx *Transaction712) From() common.Address { return tx.inner.From }
+
+// To returns the recipient address, or nil for contract creation.
+func (tx *Transaction712) To() *common.Address {
+	if tx.inner.To == nil {
+		return nil
+	}
+	to := *tx.inner.To
+	return &to
+}
+
+// GasPerPubdata returns the gasPerPubdata limit of the transaction.
+func (tx *Transaction712) GasPerPubdata() *big.Int {
+	return new(big.Int).Set(tx.inner.Meta.GasPerPubdata)
+}
+
+// FactoryDeps returns the bytecodes attached to the transaction.
+func (tx *Transaction712) FactoryDeps() [][]byte {
+	deps := make([][]byte, len(tx.inner.Meta.FactoryDeps))
+	for i, dep := range tx.inner.Meta.FactoryDeps {
+		deps[i] = common.CopyBytes(dep)
+	}
+	return deps
+}
+
+// CustomSignature returns the signature carried by the transaction, if any.
+func (tx *Transaction712) CustomSignature() []byte {
+	return common.CopyBytes(tx.inner.Meta.CustomSignature)
+}
+
+// PaymasterParams returns the paymaster settings, or nil if none are set.
+func (tx *Transaction712) PaymasterParams() *PaymasterParams {
+	if tx.inner.Meta.PaymasterParams == nil {
+		return nil
+	}
+	return &PaymasterParams{
+		Paymaster:      tx.inner.Meta.PaymasterParams.Paymaster,
+		PaymasterInput: common.CopyBytes(tx.inner.Meta.PaymasterParams.PaymasterInput),
+	}
+}
+
+// WithSignature returns a copy of the transaction carrying the given custom signature.
+func (tx *Transaction712) WithSignature(sig []byte) *Transaction712 {
+	cpy := NewTransaction712(&tx.inner)
+	cpy.inner.Meta.CustomSignature = common.CopyBytes(sig)
+	return cpy
+}
+
+// SigningHash returns the zkSync typed-data digest that the sender signs.
+// The domain is {name: "zkSync", version: "2", chainId}.
+func (tx *Transaction712) SigningHash() (common.Hash, error) {
+	structHash, err := tx.structHash()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	domain := crypto.Keccak256(
+		eip712DomainTypeHash.Bytes(),
+		crypto.Keccak256([]byte("zkSync")),
+		crypto.Keccak256([]byte("2")),
+		math.U256Bytes(new(big.Int).Set(tx.inner.ChainID)),
+	)
+	return crypto.Keccak256Hash([]byte("\x19\x01"), domain, structHash), nil
+}
+
+// Hash returns the zkSync transaction hash.
+// It is derived from the signing digest and the custom signature, so the
+// transaction must be signed first; unsigned transactions return a zero hash.
+func (tx *Transaction712) Hash() common.Hash {
+	if len(tx.inner.Meta.CustomSignature) == 0 {
+		return common.Hash{}
+	}
+	digest, err := tx.SigningHash()
+	if err != nil {
+		return common.Hash{}
+	}
+	return crypto.Keccak256Hash(digest.Bytes(), crypto.Keccak256(tx.inner.Meta.CustomSignature))
+}
+
+// MarshalBinary returns the canonical 0x71-prefixed RLP encoding of the transaction.
+func (tx *Transaction712) MarshalBinary() ([]byte, error) {
+	enc := tx712RLP{
+		Nonce:           tx.inner.Nonce,
+		GasTipCap:       tx.inner.GasTipCap,
+		GasFeeCap:       tx.inner.GasFeeCap,
+		Gas:             tx.inner.Gas,
+		Value:           tx.inner.Value,
+		Data:            tx.inner.Data,
+		V:               tx.inner.ChainID,
+		ChainID:         tx.inner.ChainID,
+		From:            tx.inner.From,
+		GasPerPubdata:   tx.inner.Meta.GasPerPubdata,
+		FactoryDeps:     tx.inner.Meta.FactoryDeps,
+		CustomSignature: tx.inner.Meta.CustomSignature,
+		PaymasterParams: [][]byte{},
+	}
+	if enc.FactoryDeps == nil {
+		enc.FactoryDeps = [][]byte{}
+	}
+	if tx.inner.To != nil {
+		enc.To = tx.inner.To.Bytes()
+	}
+	if pp := tx.inner.Meta.PaymasterParams; pp != nil {
+		enc.PaymasterParams = [][]byte{pp.Paymaster.Bytes(), pp.PaymasterInput}
+	}
+	payload, err := rlp.EncodeToBytes(&enc)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{EIP712TxType}, payload...), nil
+}
+
+// UnmarshalBinary decodes the 0x71-prefixed RLP encoding of a transaction.
+func (tx *Transaction712) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 || b[0] != EIP712TxType {
+		return errors.New("not an EIP-712 transaction")
+	}
+	var dec tx712RLP
+	if err := rlp.DecodeBytes(b[1:], &dec); err != nil {
+		return err
+	}
+	inner := EIP712Tx{
+		ChainID:   dec.ChainID,
+		Nonce:     dec.Nonce,
+		GasTipCap: dec.GasTipCap,
+		GasFeeCap: dec.GasFeeCap,
+		Gas:       dec.Gas,
+		Value:     dec.Value,
+		Data:      dec.Data,
+		From:      dec.From,
+		Meta: EIP712Meta{
+			GasPerPubdata:   dec.GasPerPubdata,
+			FactoryDeps:     dec.FactoryDeps,
+			CustomSignature: dec.CustomSignature,
+		},
+	}
+	switch len(dec.To) {
+	case 0:
+	case common.AddressLength:
+		to := common.BytesToAddress(dec.To)
+		inner.To = &to
+	default:
+		return fmt.Errorf("invalid recipient length %d", len(dec.To))
+	}
+	switch len(dec.PaymasterParams) {
+	case 0:
+	case 2:
+		if len(dec.PaymasterParams[0]) != common.AddressLength {
+			return errors.New("invalid paymaster address")
+		}
+		inner.Meta.PaymasterParams = &PaymasterParams{
+			Paymaster:      common.BytesToAddress(dec.PaymasterParams[0]),
+			PaymasterInput: dec.PaymasterParams[1],
+		}
+	default:
+		return errors.New("invalid paymaster params")
+	}
+	*tx = *NewTransaction712(&inner)
+	return nil
+}
+
+// structHash computes the EIP-712 struct hash of the Transaction type.
+func (tx *Transaction712) structHash() ([]byte, error) {
+	var to, paymaster common.Address
+	var paymasterInput []byte
+	if tx.inner.To != nil {
+		to = *tx.inner.To
+	}
+	if pp := tx.inner.Meta.PaymasterParams; pp != nil {
+		paymaster = pp.Paymaster
+		paymasterInput = pp.PaymasterInput
+	}
+
+	depHashes := make([]byte, 0, 32*len(tx.inner.Meta.FactoryDeps))
+	for _, dep := range tx.inner.Meta.FactoryDeps {
+		h, err := HashBytecode(dep)
+		if err != nil {
+			return nil, err
+		}
+		depHashes = append(depHashes, h.Bytes()...)
+	}
+
+	return crypto.Keccak256(
+		eip712TxTypeHash.Bytes(),
+		math.U256Bytes(big.NewInt(EIP712TxType)),
+		common.LeftPadBytes(tx.inner.From.Bytes(), 32),
+		common.LeftPadBytes(to.Bytes(), 32),
+		math.U256Bytes(new(big.Int).SetUint64(tx.inner.Gas)),
+		math.U256Bytes(new(big.Int).Set(tx.inner.Meta.GasPerPubdata)),
+		math.U256Bytes(new(big.Int).Set(tx.inner.GasFeeCap)),
+		math.U256Bytes(new(big.Int).Set(tx.inner.GasTipCap)),
+		common.LeftPadBytes(paymaster.Bytes(), 32),
+		math.U256Bytes(new(big.Int).SetUint64(tx.inner.Nonce)),
+		math.U256Bytes(new(big.Int).Set(tx.inner.Value)),
+		crypto.Keccak256(tx.inner.Data),
+		crypto.Keccak256(depHashes),
+		crypto.Keccak256(paymasterInput),
+	), nil
+}
+
+// HashBytecode returns the versioned zkSync bytecode hash of the given bytecode.
+// The bytecode must be a whole, odd number of 32-byte words below 2^16 words.
+func HashBytecode(bytecode []byte) (common.Hash, error) {
+	if len(bytecode)%32 != 0 {
+		return common.Hash{}, errors.New("bytecode length must be a multiple of 32 bytes")
+	}
+	words := len(bytecode) / 32
+	if words >= 1<<16 {
+		return common.Hash{}, errors.New("bytecode is too long")
+	}
+	if words%2 == 0 {
+		return common.Hash{}, errors.New("bytecode length in words must be odd")
+	}
+
+	hash := sha256.Sum256(bytecode)
+	hash[0] = 1 // version
+	hash[1] = 0
+	binary.BigEndian.PutUint16(hash[2:4], uint16(words))
+	return common.Hash(hash), nil
+}
+
+// bigOrZero returns a copy of v, or zero if v is nil.
+func bigOrZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(v)
+}
@@ -0,0 +1,265 @@
+package clients
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// devKey is the first account of the well-known hardhat/anvil development mnemonic.
+const devKey = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+var devAddress = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+// testTx712 returns a transaction using every zkSync-specific field.
+func testTx712(t *testing.T) *Transaction712 {
+	t.Helper()
+	to := common.HexToAddress("0xa61464658AfeAf65CccaaFD3a512b69A83B77618")
+	dep := make([]byte, 64)
+	dep[63] = 0x01
+	dep = append(dep, make([]byte, 32)...) // 3 words: an odd word count is required
+	return NewTransaction712(&EIP712Tx{
+		ChainID:   big.NewInt(11124),
+		Nonce:     7,
+		GasTipCap: big.NewInt(1_000_000),
+		GasFeeCap: big.NewInt(250_000_000),
+		Gas:       300_000,
+		To:        &to,
+		Value:     big.NewInt(1e15),
+		Data:      hexutil.MustDecode("0xa9059cbb"),
+		From:      devAddress,
+		Meta: EIP712Meta{
+			GasPerPubdata: big.NewInt(DefaultGasPerPubdata),
+			FactoryDeps:   [][]byte{dep},
+			PaymasterParams: &PaymasterParams{
+				Paymaster:      common.HexToAddress("0x00000000000000000000000000000000000000bb"),
+				PaymasterInput: hexutil.MustDecode("0x8c5a344500000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000000"),
+			},
+		},
+	})
+}
+
+func addressUint(addr common.Address) string {
+	return new(big.Int).SetBytes(addr.Bytes()).String()
+}
+
+// The digest must match go-ethereum's independent EIP-712 implementation fed with the
+// Transaction type zkSync's contracts hash.
+func TestTransaction712SigningHashMatchesTypedData(t *testing.T) {
+	tx := testTx712(t)
+	depHash, err := HashBytecode(tx.FactoryDeps()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	pp := tx.PaymasterParams()
+
+	typed := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			"Transaction": {
+				{Name: "txType", Type: "uint256"},
+				{Name: "from", Type: "uint256"},
+				{Name: "to", Type: "uint256"},
+				{Name: "gasLimit", Type: "uint256"},
+				{Name: "gasPerPubdataByteLimit", Type: "uint256"},
+				{Name: "maxFeePerGas", Type: "uint256"},
+				{Name: "maxPriorityFeePerGas", Type: "uint256"},
+				{Name: "paymaster", Type: "uint256"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "value", Type: "uint256"},
+				{Name: "data", Type: "bytes"},
+				{Name: "factoryDeps", Type: "bytes32[]"},
+				{Name: "paymasterInput", Type: "bytes"},
+			},
+		},
+		PrimaryType: "Transaction",
+		Domain: apitypes.TypedDataDomain{
+			Name:    "zkSync",
+			Version: "2",
+			ChainId: (*math.HexOrDecimal256)(big.NewInt(11124)),
+		},
+		Message: apitypes.TypedDataMessage{
+			"txType":                 "113",
+			"from":                   addressUint(tx.From()),
+			"to":                     addressUint(*tx.To()),
+			"gasLimit":               "300000",
+			"gasPerPubdataByteLimit": "50000",
+			"maxFeePerGas":           "250000000",
+			"maxPriorityFeePerGas":   "1000000",
+			"paymaster":              addressUint(pp.Paymaster),
+			"nonce":                  "7",
+			"value":                  "1000000000000000",
+			"data":                   hexutil.Encode(tx.Data()),
+			"factoryDeps":            []interface{}{depHash.Hex()},
+			"paymasterInput":         hexutil.Encode(pp.PaymasterInput),
+		},
+	}
+	want, _, err := apitypes.TypedDataAndHash(typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := tx.SigningHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Fatalf("signing hash = %s, want %x", got, want)
+	}
+}
+
+// The payload is the field list zkSync nodes decode, encoded here independently.
+func TestTransaction712MarshalBinaryLayout(t *testing.T) {
+	w, err := FromPrivateKey(devKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := w.SignTx712(testTx712(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pp := tx.PaymasterParams()
+
+	payload, err := rlp.EncodeToBytes([]interface{}{
+		uint64(7),
+		big.NewInt(1_000_000),
+		big.NewInt(250_000_000),
+		uint64(300_000),
+		tx.To().Bytes(),
+		big.NewInt(1e15),
+		tx.Data(),
+		big.NewInt(11124), // v carries the chain ID, r and s are empty
+		[]byte{},
+		[]byte{},
+		big.NewInt(11124),
+		devAddress,
+		big.NewInt(DefaultGasPerPubdata),
+		tx.FactoryDeps(),
+		tx.CustomSignature(),
+		[]interface{}{pp.Paymaster.Bytes(), pp.PaymasterInput},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte{EIP712TxType}, payload...)
+
+	got, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("encoding = %x\nwant       %x", got, want)
+	}
+}
+
+func TestTransaction712RoundTrip(t *testing.T) {
+	w, err := FromPrivateKey(devKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tx := range map[string]*Transaction712{
+		"full":    testTx712(t),
+		"minimal": NewTransaction712(&EIP712Tx{ChainID: big.NewInt(2741), From: devAddress}),
+	} {
+		signed, err := w.SignTx712(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := signed.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded Transaction712
+		if err := decoded.UnmarshalBinary(raw); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		again, err := decoded.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(raw, again) {
+			t.Fatalf("%s: re-encoding differs:\n%x\n%x", name, raw, again)
+		}
+		if decoded.Hash() != signed.Hash() || decoded.From() != devAddress || decoded.Nonce() != signed.Nonce() {
+			t.Fatalf("%s: decoded %+v, want %+v", name, decoded.inner, signed.inner)
+		}
+		if (decoded.To() == nil) != (signed.To() == nil) || (decoded.PaymasterParams() == nil) != (signed.PaymasterParams() == nil) {
+			t.Fatalf("%s: optional fields lost in the round trip", name)
+		}
+	}
+
+	var tx Transaction712
+	if err := tx.UnmarshalBinary([]byte{0x02, 0xc0}); err == nil {
+		t.Fatal("decoded a non-0x71 transaction")
+	}
+}
+
+func TestSignTx712RecoversSender(t *testing.T) {
+	w, err := FromPrivateKey(devKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := w.SignTx712(testTx712(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := tx.CustomSignature()
+	if len(sig) != 65 || (sig[64] != 27 && sig[64] != 28) {
+		t.Fatalf("signature %x, want 65 bytes with v 27 or 28", sig)
+	}
+	digest, err := tx.SigningHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recoverable := common.CopyBytes(sig)
+	recoverable[64] -= 27
+	pub, err := crypto.SigToPub(digest.Bytes(), recoverable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := crypto.PubkeyToAddress(*pub); got != devAddress {
+		t.Fatalf("recovered %s, want %s", got, devAddress)
+	}
+}
+
+// zkSync hashes a transaction as keccak256(signing digest || keccak256(signature)).
+func TestTransaction712Hash(t *testing.T) {
+	unsigned := testTx712(t)
+	if unsigned.Hash() != (common.Hash{}) {
+		t.Fatalf("unsigned hash = %s, want zero", unsigned.Hash())
+	}
+	w, err := FromPrivateKey(devKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := w.SignTx712(unsigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := tx.SigningHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := crypto.Keccak256Hash(digest.Bytes(), crypto.Keccak256(tx.CustomSignature()))
+	if tx.Hash() != want {
+		t.Fatalf("hash = %s, want %s", tx.Hash(), want)
+	}
+
+	other, err := w.SignTx712(NewTransaction712(&EIP712Tx{ChainID: big.NewInt(11124), Nonce: 8, From: devAddress}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Hash() == tx.Hash() {
+		t.Fatal("different transactions share a hash")
+	}
+}
@@ -74,11 +74,11 @@ func NewERC20(client *Client, token common.Address, abiJSON string) (*ERC20, err
 }
 
 // BalanceOf returns the token balance of the given address.
-// Calls the ERC20 `balanceOf` method as a read-only contract call.
-func (t *ERC20) BalanceOf(ctx context.Context, owner common.Address) (*big.Int, error) {
+// Calls the ERC20 `balanceOf` method as a read-only contract call at the block selected by opts.
+func (t *ERC20) BalanceOf(ctx context.Context, owner common.Address, opts ...CallOption) (*big.Int, error) {
 	data, _ := t.abi.Pack("balanceOf", owner)
 	msg := ethereum.CallMsg{To: &t.addr, Data: data}
-	res, err := t.client.CallContract(ctx, msg)
+	res, err := t.client.CallContract(ctx, msg, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -88,11 +88,11 @@ func (t *ERC20) BalanceOf(ctx context.Context, owner common.Address) (*big.Int,
 }
 
 // Allowance returns the remaining tokens a spender can spend from an owner's account.
-// Calls the ERC20 `allowance` method as a read-only contract call.
-func (t *ERC20) Allowance(ctx context.Context, owner, spender common.Address) (*big.Int, error) {
+// Calls the ERC20 `allowance` method as a read-only contract call at the block selected by opts.
+func (t *ERC20) Allowance(ctx context.Context, owner, spender common.Address, opts ...CallOption) (*big.Int, error) {
 	data, _ := t.abi.Pack("allowance", owner, spender)
 	msg := ethereum.CallMsg{To: &t.addr, Data: data}
-	res, err := t.client.CallContract(ctx, msg)
+	res, err := t.client.CallContract(ctx, msg, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -102,11 +102,11 @@ func (t *ERC20) Allowance(ctx context.Context, owner, spender common.Address) (*
 }
 
 // Name returns the name of the ERC20 token.
-// Calls the ERC20 `name` method as a read-only contract call.
-func (t *ERC20) Name(ctx context.Context) (string, error) {
+// Calls the ERC20 `name` method as a read-only contract call at the block selected by opts.
+func (t *ERC20) Name(ctx context.Context, opts ...CallOption) (string, error) {
 	data, _ := t.abi.Pack("name")
 	msg := ethereum.CallMsg{To: &t.addr, Data: data}
-	res, err := t.client.CallContract(ctx, msg)
+	res, err := t.client.CallContract(ctx, msg, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -116,11 +116,11 @@ func (t *ERC20) Name(ctx context.Context) (string, error) {
 }
 
 // Symbol returns the symbol of the ERC20 token.
-// Calls the ERC20 `symbol` method as a read-only contract call.
-func (t *ERC20) Symbol(ctx context.Context) (string, error) {
+// Calls the ERC20 `symbol` method as a read-only contract call at the block selected by opts.
+func (t *ERC20) Symbol(ctx context.Context, opts ...CallOption) (string, error) {
 	data, _ := t.abi.Pack("symbol")
 	msg := ethereum.CallMsg{To: &t.addr, Data: data}
-	res, err := t.client.CallContract(ctx, msg)
+	res, err := t.client.CallContract(ctx, msg, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -130,11 +130,11 @@ func (t *ERC20) Symbol(ctx context.Context) (string, error) {
 }
 
 // Decimals returns the number of decimals used by the ERC20 token.
-// Calls the ERC20 `decimals` method as a read-only contract call.
-func (t *ERC20) Decimals(ctx context.Context) (uint8, error) {
+// Calls the ERC20 `decimals` method as a read-only contract call at the block selected by opts.
+func (t *ERC20) Decimals(ctx context.Context, opts ...CallOption) (uint8, error) {
 	data, _ := t.abi.Pack("decimals")
 	msg := ethereum.CallMsg{To: &t.addr, Data: data}
-	res, err := t.client.CallContract(ctx, msg)
+	res, err := t.client.CallContract(ctx, msg, opts...)
 	if err != nil {
 		return 0, err
 	}
@@ -145,35 +145,28 @@ func (t *ERC20) Decimals(ctx context.Context) (uint8, error) {
 
 // Transfer sends a transaction to transfer tokens to another address.
 // Calls the ERC20 `transfer` method as a write transaction.
-func (t *ERC20) Transfer(ctx context.Context, wallet *Wallet, to common.Address, amount *big.Int) (*types.Transaction, error) {
+func (t *ERC20) Transfer(ctx context.Context, wallet *Wallet, to common.Address, amount *big.Int, opts ...TxOption) (Tx, error) {
 	data, _ := t.abi.Pack("transfer", to, amount)
-	nm := NewNonceManager(t.client, wallet.Address)
-	return wallet.BuildAndSendTx(ctx, t.client, &t.addr, big.NewInt(0), data, nm)
+	return wallet.BuildAndSendTx(ctx, t.client, &t.addr, big.NewInt(0), data, nil, opts...)
 }
 
 // TransferFrom sends a transaction to transfer tokens from one address to another.
 // Calls the ERC20 `transferFrom` method as a write transaction.
-func (t *ERC20) TransferFrom(ctx context.Context, wallet *Wallet, from common.Address, to common.Address, amount *big.Int) (*types.Transaction, error) {
+func (t *ERC20) TransferFrom(ctx context.Context, wallet *Wallet, from common.Address, to common.Address, amount *big.Int, opts ...TxOption) (Tx, error) {
 	data, _ := t.abi.Pack("transferFrom", from, to, amount)
-	nm := NewNonceManager(t.client, wallet.Address)
-	return wallet.BuildAndSendTx(ctx, t.client, &t.addr, big.NewInt(0), data, nm)
+	return wallet.BuildAndSendTx(ctx, t.client, &t.addr, big.NewInt(0), data, nil, opts...)
 }
 
 // Approve sends a transaction to approve a spender for a specific amount.
 // Calls the ERC20 `approve` method as a write transaction.
-func (t *ERC20) Approve(ctx context.Context, wallet *Wallet, spender common.Address, amount *big.Int) (*types.Transaction, error) {
+func (t *ERC20) Approve(ctx context.Context, wallet *Wallet, spender common.Address, amount *big.Int, opts ...TxOption) (Tx, error) {
 	data, _ := t.abi.Pack("approve", spender, amount)
-	nm := NewNonceManager(t.client, wallet.Address)
-	return wallet.BuildAndSendTx(ctx, t.client, &t.addr, big.NewInt(0), data, nm)
+	return wallet.BuildAndSendTx(ctx, t.client, &t.addr, big.NewInt(0), data, nil, opts...)
 }
 
 // WatchTransfers subscribes to Transfer events and sends them to the provided channel.
-// Requires a WebSocket client; parses logs into ERC20TransferEvent structs.
+// Works over WebSocket or HTTP (polled); parses logs into ERC20TransferEvent structs.
 func (t *ERC20) WatchTransfers(ctx context.Context, from, to *common.Address, ch chan<- ERC20TransferEvent) error {
-	if !t.client.isWS {
-		return fmt.Errorf("WatchTransfers requires a WS client")
-	}
-
 	transferSig := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
 
 	query := ethereum.FilterQuery{
@@ -191,7 +184,7 @@ func (t *ERC20) WatchTransfers(ctx context.Context, from, to *common.Address, ch
 	}
 
 	logsCh := make(chan types.Log)
-	sub, err := t.client.SubscribeLogs(ctx, query, logsCh)
+	sub, err := t.client.resubscribeLogs(ctx, query, logsCh, "erc20.Transfer", LogKeyContract, t.addr)
 	if err != nil {
 		return err
 	}
@@ -200,12 +193,13 @@ func (t *ERC20) WatchTransfers(ctx context.Context, from, to *common.Address, ch
 		defer sub.Unsubscribe()
 		for {
 			select {
-			case err := <-sub.Err():
-				fmt.Println("subscription error:", err)
+			case <-sub.Err():
 				return
 			case vLog := <-logsCh:
 				event, err := t.parseTransferLog(vLog)
+				t.client.instrumentation().SubscriptionEvent(ctx, "erc20.Transfer", err)
 				if err != nil {
+					t.client.Logger().WarnContext(ctx, "skipping malformed log", LogKeyKind, "erc20.Transfer", LogKeyContract, t.addr, "tx", vLog.TxHash, LogKeyError, err)
 					continue
 				}
 				ch <- *event
@@ -216,12 +210,8 @@ func (t *ERC20) WatchTransfers(ctx context.Context, from, to *common.Address, ch
 }
 
 // WatchApprovals subscribes to Approval events and sends them to the provided channel.
-// Requires a WebSocket client; parses logs into ERC20ApprovalEvent structs.
+// Works over WebSocket or HTTP (polled); parses logs into ERC20ApprovalEvent structs.
 func (t *ERC20) WatchApprovals(ctx context.Context, owner, spender *common.Address, ch chan<- ERC20ApprovalEvent) error {
-	if !t.client.isWS {
-		return fmt.Errorf("WatchApprovals requires a WS client")
-	}
-
 	approvalSig := crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
 
 	query := ethereum.FilterQuery{
@@ -239,7 +229,7 @@ func (t *ERC20) WatchApprovals(ctx context.Context, owner, spender *common.Addre
 	}
 
 	logsCh := make(chan types.Log)
-	sub, err := t.client.SubscribeLogs(ctx, query, logsCh)
+	sub, err := t.client.resubscribeLogs(ctx, query, logsCh, "erc20.Approval", LogKeyContract, t.addr)
 	if err != nil {
 		return err
 	}
@@ -248,12 +238,13 @@ func (t *ERC20) WatchApprovals(ctx context.Context, owner, spender *common.Addre
 		defer sub.Unsubscribe()
 		for {
 			select {
-			case err := <-sub.Err():
-				fmt.Println("subscription error:", err)
+			case <-sub.Err():
 				return
 			case vLog := <-logsCh:
 				event, err := t.parseApprovalLog(vLog)
+				t.client.instrumentation().SubscriptionEvent(ctx, "erc20.Approval", err)
 				if err != nil {
+					t.client.Logger().WarnContext(ctx, "skipping malformed log", LogKeyKind, "erc20.Approval", LogKeyContract, t.addr, "tx", vLog.TxHash, LogKeyError, err)
 					continue
 				}
 				ch <- *event
@@ -3,7 +3,6 @@ package clients
 import (
 	"context"
 	"errors"
-	"fmt"
 	"math/big"
 	"strings"
 
@@ -72,10 +71,10 @@ func NewERC721(client *Client, contractAddr common.Address, abiJSON string) (*ER
 }
 
 // BalanceOf returns the number of NFTs owned by the given address.
-// Calls the ERC721 `balanceOf` method as a read-only contract call.
-func (e *ERC721) BalanceOf(ctx context.Context, owner common.Address) (*big.Int, error) {
+// Calls the ERC721 `balanceOf` method as a read-only contract call at the block selected by opts.
+func (e *ERC721) BalanceOf(ctx context.Context, owner common.Address, opts ...CallOption) (*big.Int, error) {
 	data, _ := e.abi.Pack("balanceOf", owner)
-	res, err := e.client.CallContract(ctx, ethereum.CallMsg{To: &e.addr, Data: data})
+	res, err := e.client.CallContract(ctx, ethereum.CallMsg{To: &e.addr, Data: data}, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -84,10 +83,10 @@ func (e *ERC721) BalanceOf(ctx context.Context, owner common.Address) (*big.Int,
 }
 
 // OwnerOf returns the owner address of the specified token ID.
-// Calls the ERC721 `ownerOf` method as a read-only contract call.
-func (e *ERC721) OwnerOf(ctx context.Context, tokenID *big.Int) (common.Address, error) {
+// Calls the ERC721 `ownerOf` method as a read-only contract call at the block selected by opts.
+func (e *ERC721) OwnerOf(ctx context.Context, tokenID *big.Int, opts ...CallOption) (common.Address, error) {
 	data, _ := e.abi.Pack("ownerOf", tokenID)
-	res, err := e.client.CallContract(ctx, ethereum.CallMsg{To: &e.addr, Data: data})
+	res, err := e.client.CallContract(ctx, ethereum.CallMsg{To: &e.addr, Data: data}, opts...)
 	if err != nil {
 		return common.Address{}, err
 	}
@@ -96,10 +95,10 @@ func (e *ERC721) OwnerOf(ctx context.Context, tokenID *big.Int) (common.Address,
 }
 
 // TokenURI returns the metadata URI for the specified token ID.
-// Calls the ERC721 `tokenURI` method as a read-only contract call.
-func (e *ERC721) TokenURI(ctx context.Context, tokenID *big.Int) (string, error) {
+// Calls the ERC721 `tokenURI` method as a read-only contract call at the block selected by opts.
+func (e *ERC721) TokenURI(ctx context.Context, tokenID *big.Int, opts ...CallOption) (string, error) {
 	data, _ := e.abi.Pack("tokenURI", tokenID)
-	res, err := e.client.CallContract(ctx, ethereum.CallMsg{To: &e.addr, Data: data})
+	res, err := e.client.CallContract(ctx, ethereum.CallMsg{To: &e.addr, Data: data}, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -108,10 +107,10 @@ func (e *ERC721) TokenURI(ctx context.Context, tokenID *big.Int) (string, error)
 }
 
 // GetApproved returns the address approved for the given token ID.
-// Calls the ERC721 `getApproved` method as a read-only contract call.
-func (e *ERC721) GetApproved(ctx context.Context, tokenID *big.Int) (common.Address, error) {
+// Calls the ERC721 `getApproved` method as a read-only contract call at the block selected by opts.
+func (e *ERC721) GetApproved(ctx context.Context, tokenID *big.Int, opts ...CallOption) (common.Address, error) {
 	data, _ := e.abi.Pack("getApproved", tokenID)
-	res, err := e.client.CallContract(ctx, ethereum.CallMsg{To: &e.addr, Data: data})
+	res, err := e.client.CallContract(ctx, ethereum.CallMsg{To: &e.addr, Data: data}, opts...)
 	if err != nil {
 		return common.Address{}, err
 	}
@@ -120,10 +119,10 @@ func (e *ERC721) GetApproved(ctx context.Context, tokenID *big.Int) (common.Addr
 }
 
 // IsApprovedForAll checks if an operator is approved for all tokens of an owner.
-// Calls the ERC721 `isApprovedForAll` method as a read-only contract call.
-func (e *ERC721) IsApprovedForAll(ctx context.Context, owner, operator common.Address) (bool, error) {
+// Calls the ERC721 `isApprovedForAll` method as a read-only contract call at the block selected by opts.
+func (e *ERC721) IsApprovedForAll(ctx context.Context, owner, operator common.Address, opts ...CallOption) (bool, error) {
 	data, _ := e.abi.Pack("isApprovedForAll", owner, operator)
-	res, err := e.client.CallContract(ctx, ethereum.CallMsg{To: &e.addr, Data: data})
+	res, err := e.client.CallContract(ctx, ethereum.CallMsg{To: &e.addr, Data: data}, opts...)
 	if err != nil {
 		return false, err
 	}
@@ -133,41 +132,35 @@ func (e *ERC721) IsApprovedForAll(ctx context.Context, owner, operator common.Ad
 
 // TransferFrom sends a transaction to transfer a token from one address to another.
 // Calls the ERC721 `transferFrom` method as a write transaction.
-func (e *ERC721) TransferFrom(ctx context.Context, wallet *Wallet, from, to common.Address, tokenID *big.Int) (*types.Transaction, error) {
+func (e *ERC721) TransferFrom(ctx context.Context, wallet *Wallet, from, to common.Address, tokenID *big.Int, opts ...TxOption) (Tx, error) {
 	data, _ := e.abi.Pack("transferFrom", from, to, tokenID)
-	nm := NewNonceManager(e.client, wallet.Address)
-	return wallet.BuildAndSendTx(ctx, e.client, &e.addr, big.NewInt(0), data, nm)
+	return wallet.BuildAndSendTx(ctx, e.client, &e.addr, big.NewInt(0), data, nil, opts...)
 }
 
 // Approve sends a transaction to approve an address for a specific token ID.
 // Calls the ERC721 `approve` method as a write transaction.
-func (e *ERC721) Approve(ctx context.Context, wallet *Wallet, to common.Address, tokenID *big.Int) (*types.Transaction, error) {
+func (e *ERC721) Approve(ctx context.Context, wallet *Wallet, to common.Address, tokenID *big.Int, opts ...TxOption) (Tx, error) {
 	data, _ := e.abi.Pack("approve", to, tokenID)
-	nm := NewNonceManager(e.client, wallet.Address)
-	return wallet.BuildAndSendTx(ctx, e.client, &e.addr, big.NewInt(0), data, nm)
+	return wallet.BuildAndSendTx(ctx, e.client, &e.addr, big.NewInt(0), data, nil, opts...)
 }
 
 // SetApprovalForAll sends a transaction to set or unset operator approval for all tokens.
 // Calls the ERC721 `setApprovalForAll` method as a write transaction.
-func (e *ERC721) SetApprovalForAll(ctx context.Context, wallet *Wallet, operator common.Address, approved bool) (*types.Transaction, error) {
+func (e *ERC721) SetApprovalForAll(ctx context.Context, wallet *Wallet, operator common.Address, approved bool, opts ...TxOption) (Tx, error) {
 	data, _ := e.abi.Pack("setApprovalForAll", operator, approved)
-	nm := NewNonceManager(e.client, wallet.Address)
-	return wallet.BuildAndSendTx(ctx, e.client, &e.addr, big.NewInt(0), data, nm)
+	return wallet.BuildAndSendTx(ctx, e.client, &e.addr, big.NewInt(0), data, nil, opts...)
 }
 
 // WatchTransfers subscribes to Transfer events and sends them to the provided channel.
-// Requires a WebSocket client; parses logs into ERC721TransferEvent structs.
+// Works over WebSocket or HTTP (polled); parses logs into ERC721TransferEvent structs.
 func (e *ERC721) WatchTransfers(ctx context.Context, ch chan<- ERC721TransferEvent) error {
-	if !e.client.isWS {
-		return fmt.Errorf("WatchTransfers requires a WS client")
-	}
 	transferSig := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{e.addr},
 		Topics:    [][]common.Hash{{transferSig}},
 	}
 	logsCh := make(chan types.Log)
-	sub, err := e.client.SubscribeLogs(ctx, query, logsCh)
+	sub, err := e.client.resubscribeLogs(ctx, query, logsCh, "erc721.Transfer", LogKeyContract, e.addr)
 	if err != nil {
 		return err
 	}
@@ -175,12 +168,13 @@ func (e *ERC721) WatchTransfers(ctx context.Context, ch chan<- ERC721TransferEve
 		defer sub.Unsubscribe()
 		for {
 			select {
-			case err := <-sub.Err():
-				fmt.Println("subscription error:", err)
+			case <-sub.Err():
 				return
 			case vLog := <-logsCh:
 				event, err := parseERC721TransferLog(vLog)
+				e.client.instrumentation().SubscriptionEvent(ctx, "erc721.Transfer", err)
 				if err != nil {
+					e.client.Logger().WarnContext(ctx, "skipping malformed log", LogKeyKind, "erc721.Transfer", LogKeyContract, e.addr, "tx", vLog.TxHash, LogKeyError, err)
 					continue
 				}
 				ch <- *event
@@ -191,18 +185,15 @@ func (e *ERC721) WatchTransfers(ctx context.Context, ch chan<- ERC721TransferEve
 }
 
 // WatchApprovals subscribes to Approval events and sends them to the provided channel.
-// Requires a WebSocket client; parses logs into ERC721ApprovalEvent structs.
+// Works over WebSocket or HTTP (polled); parses logs into ERC721ApprovalEvent structs.
 func (e *ERC721) WatchApprovals(ctx context.Context, ch chan<- ERC721ApprovalEvent) error {
-	if !e.client.isWS {
-		return fmt.Errorf("WatchApprovals requires a WS client")
-	}
 	approvalSig := crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{e.addr},
 		Topics:    [][]common.Hash{{approvalSig}},
 	}
 	logsCh := make(chan types.Log)
-	sub, err := e.client.SubscribeLogs(ctx, query, logsCh)
+	sub, err := e.client.resubscribeLogs(ctx, query, logsCh, "erc721.Approval", LogKeyContract, e.addr)
 	if err != nil {
 		return err
 	}
@@ -210,12 +201,13 @@ func (e *ERC721) WatchApprovals(ctx context.Context, ch chan<- ERC721ApprovalEve
 		defer sub.Unsubscribe()
 		for {
 			select {
-			case err := <-sub.Err():
-				fmt.Println("subscription error:", err)
+			case <-sub.Err():
 				return
 			case vLog := <-logsCh:
 				event, err := parseERC721ApprovalLog(vLog)
+				e.client.instrumentation().SubscriptionEvent(ctx, "erc721.Approval", err)
 				if err != nil {
+					e.client.Logger().WarnContext(ctx, "skipping malformed log", LogKeyKind, "erc721.Approval", LogKeyContract, e.addr, "tx", vLog.TxHash, LogKeyError, err)
 					continue
 				}
 				ch <- *event
@@ -226,18 +218,15 @@ func (e *ERC721) WatchApprovals(ctx context.Context, ch chan<- ERC721ApprovalEve
 }
 
 // WatchApprovalForAll subscribes to ApprovalForAll events and sends them to the provided channel.
-// Requires a WebSocket client; parses logs into ERC721ApprovalForAllEvent structs.
+// Works over WebSocket or HTTP (polled); parses logs into ERC721ApprovalForAllEvent structs.
 func (e *ERC721) WatchApprovalForAll(ctx context.Context, ch chan<- ERC721ApprovalForAllEvent) error {
-	if !e.client.isWS {
-		return fmt.Errorf("WatchApprovalForAll requires a WS client")
-	}
 	sig := crypto.Keccak256Hash([]byte("ApprovalForAll(address,address,bool)"))
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{e.addr},
 		Topics:    [][]common.Hash{{sig}},
 	}
 	logsCh := make(chan types.Log)
-	sub, err := e.client.SubscribeLogs(ctx, query, logsCh)
+	sub, err := e.client.resubscribeLogs(ctx, query, logsCh, "erc721.ApprovalForAll", LogKeyContract, e.addr)
 	if err != nil {
 		return err
 	}
@@ -246,12 +235,13 @@ func (e *ERC721) WatchApprovalForAll(ctx context.Context, ch chan<- ERC721Approv
 		defer sub.Unsubscribe()
 		for {
 			select {
-			case err := <-sub.Err():
-				fmt.Println("subscription error:", err)
+			case <-sub.Err():
 				return
 			case vLog := <-logsCh:
 				event, err := parseERC721ApprovalForAllLog(vLog)
+				e.client.instrumentation().SubscriptionEvent(ctx, "erc721.ApprovalForAll", err)
 				if err != nil {
+					e.client.Logger().WarnContext(ctx, "skipping malformed log", LogKeyKind, "erc721.ApprovalForAll", LogKeyContract, e.addr, "tx", vLog.TxHash, LogKeyError, err)
 					continue
 				}
 				ch <- *event
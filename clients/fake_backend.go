@@ -0,0 +1,162 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrNotScripted is returned by a FakeBackend for calls it has no response for.
+var ErrNotScripted = errors.New("no response scripted")
+
+// FakeCall is one request received by a FakeBackend.
+type FakeCall struct {
+	Method string
+	Params []json.RawMessage
+}
+
+// FakeHandler computes the response to a call from its JSON-encoded params.
+type FakeHandler func(params []json.RawMessage) (interface{}, error)
+
+// FakeBackend is an in-memory Backend for unit tests. It records every call and answers
+// with scripted responses: one-shot responses queued with Once, in order, then the
+// standing response set with On, OnError or OnFunc.
+//
+// Results are encoded to JSON as a node would return them, so script them in their
+// wire form, e.g. "0x2a", (*hexutil.Big)(amount) or hexutil.Bytes(data).
+type FakeBackend struct {
+	mu       sync.Mutex
+	standing map[string]FakeHandler
+	queued   map[string][]FakeHandler
+	calls    []FakeCall
+}
+
+// NewFakeBackend creates a FakeBackend with no scripted responses.
+// Pass it to NewClientFromBackend to get a Client.
+func NewFakeBackend() *FakeBackend {
+	return &FakeBackend{
+		standing: make(map[string]FakeHandler),
+		queued:   make(map[string][]FakeHandler),
+	}
+}
+
+// On answers every call to method with result.
+func (f *FakeBackend) On(method string, result interface{}) *FakeBackend {
+	return f.OnFunc(method, func([]json.RawMessage) (interface{}, error) { return result, nil })
+}
+
+// OnError fails every call to method with err. Errors implementing rpc.Error keep their code.
+func (f *FakeBackend) OnError(method string, err error) *FakeBackend {
+	return f.OnFunc(method, func([]json.RawMessage) (interface{}, error) { return nil, err })
+}
+
+// OnFunc answers every call to method with the result of fn.
+func (f *FakeBackend) OnFunc(method string, fn FakeHandler) *FakeBackend {
+	f.mu.Lock()
+	f.standing[method] = fn
+	f.mu.Unlock()
+	return f
+}
+
+// Once answers the next call to method with result, before any standing response.
+// Queued responses are used in the order they were added.
+func (f *FakeBackend) Once(method string, result interface{}) *FakeBackend {
+	f.mu.Lock()
+	f.queued[method] = append(f.queued[method], func([]json.RawMessage) (interface{}, error) { return result, nil })
+	f.mu.Unlock()
+	return f
+}
+
+// OnceError fails the next call to method with err, before any standing response.
+func (f *FakeBackend) OnceError(method string, err error) *FakeBackend {
+	f.mu.Lock()
+	f.queued[method] = append(f.queued[method], func([]json.RawMessage) (interface{}, error) { return nil, err })
+	f.mu.Unlock()
+	return f
+}
+
+// Calls returns every call received so far, in order.
+func (f *FakeBackend) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeCall(nil), f.calls...)
+}
+
+// CallsTo returns the calls received for method, in order.
+func (f *FakeBackend) CallsTo(method string) []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []FakeCall
+	for _, call := range f.calls {
+		if call.Method == method {
+			out = append(out, call)
+		}
+	}
+	return out
+}
+
+// Reset forgets every recorded call and scripted response.
+func (f *FakeBackend) Reset() {
+	f.mu.Lock()
+	f.standing = make(map[string]FakeHandler)
+	f.queued = make(map[string][]FakeHandler)
+	f.calls = nil
+	f.mu.Unlock()
+}
+
+// CallContext records the call and decodes its scripted response into result.
+func (f *FakeBackend) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	params := make([]json.RawMessage, len(args))
+	for i, arg := range args {
+		raw, err := json.Marshal(arg)
+		if err != nil {
+			return fmt.Errorf("encoding %s param %d: %w", method, i, err)
+		}
+		params[i] = raw
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, FakeCall{Method: method, Params: params})
+	handler := f.standing[method]
+	if queue := f.queued[method]; len(queue) > 0 {
+		handler, f.queued[method] = queue[0], queue[1:]
+	}
+	f.mu.Unlock()
+
+	if handler == nil {
+		return fmt.Errorf("%w for %s", ErrNotScripted, method)
+	}
+	value, err := handler(params)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encoding %s result: %w", method, err)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// BatchCallContext answers each element as CallContext would, setting its Error.
+func (f *FakeBackend) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	for i := range b {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b[i].Error = f.CallContext(ctx, b[i].Result, b[i].Method, b[i].Args...)
+	}
+	return nil
+}
+
+// Close does nothing; a FakeBackend holds no resources.
+func (f *FakeBackend) Close() {}
@@ -0,0 +1,189 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+// Fees are the per-gas prices a transaction is sent with.
+type Fees struct {
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+
+	// BaseFee is the base fee the caps were derived from, or nil if unknown.
+	// Legacy transactions pay BaseFee + GasTipCap when it is set, GasFeeCap otherwise.
+	BaseFee *big.Int
+}
+
+// FeeStrategy chooses the fees of a transaction. msg is the call being sent and meta its
+// EIP-712 fields (nil for Ethereum transactions), for strategies that price it individually.
+type FeeStrategy interface {
+	Fees(ctx context.Context, client *Client, msg ethereum.CallMsg, meta *EIP712Meta) (*Fees, error)
+}
+
+var (
+	// SuggestedFees uses the node's suggested tip and a fee cap of twice the latest
+	// base fee plus the tip. It is the default strategy.
+	SuggestedFees FeeStrategy = suggestedFees{}
+
+	// FeeSlow bids a low tip and no base fee headroom, for jobs that can wait,
+	// such as batch payouts. It may stall while base fees rise.
+	FeeSlow FeeStrategy = FeeHistoryStrategy{Blocks: 20, Percentile: 10, BaseFeePercent: 100}
+
+	// FeeStandard bids a median tip and doubles the base fee, riding out several full blocks.
+	FeeStandard FeeStrategy = FeeHistoryStrategy{Blocks: 20, Percentile: 50, BaseFeePercent: 200}
+
+	// FeeFast bids a high tip and triples the base fee, for time-critical sends such as liquidations.
+	FeeFast FeeStrategy = FeeHistoryStrategy{Blocks: 20, Percentile: 90, BaseFeePercent: 300}
+
+	// EstimatedFees prices each transaction with zks_estimateFee, paymaster and factory deps included.
+	EstimatedFees FeeStrategy = estimatedFees{}
+)
+
+// FeeHistoryStrategy derives fees from eth_feeHistory: the tip is the median over the
+// non-empty blocks among the last Blocks of the Percentile-th tip paid in each, and the
+// fee cap is BaseFeePercent of the next block's base fee plus the tip.
+type FeeHistoryStrategy struct {
+	Blocks         uint64  // blocks of history to sample; 0 means 20
+	Percentile     float64 // tip percentile within each block, 0-100
+	BaseFeePercent uint64  // fee cap headroom over the next base fee; 0 means 200
+}
+
+// Fees implements FeeStrategy.
+func (s FeeHistoryStrategy) Fees(ctx context.Context, client *Client, _ ethereum.CallMsg, _ *EIP712Meta) (*Fees, error) {
+	blocks, percent := s.Blocks, s.BaseFeePercent
+	if blocks == 0 {
+		blocks = 20
+	}
+	if percent == 0 {
+		percent = 200
+	}
+	history, err := client.Eth.FeeHistory(ctx, blocks, nil, []float64{s.Percentile})
+	if err != nil {
+		return nil, err
+	}
+
+	// The last base fee is the one of the block after the newest sampled block
+	var baseFee *big.Int
+	if n := len(history.BaseFee); n > 0 && history.BaseFee[n-1] != nil {
+		baseFee = history.BaseFee[n-1]
+	} else if baseFee, err = latestBaseFee(ctx, client); err != nil {
+		return nil, err
+	}
+
+	// Empty blocks report zero tips; without any full block, fall back to the node's suggestion
+	var tips []*big.Int
+	for i, reward := range history.Reward {
+		if i < len(history.GasUsedRatio) && history.GasUsedRatio[i] == 0 {
+			continue
+		}
+		if len(reward) > 0 && reward[0] != nil {
+			tips = append(tips, reward[0])
+		}
+	}
+	var tip *big.Int
+	if len(tips) > 0 {
+		sort.Slice(tips, func(i, j int) bool { return tips[i].Cmp(tips[j]) < 0 })
+		tip = new(big.Int).Set(tips[len(tips)/2])
+	} else if tip, err = client.Eth.SuggestGasTipCap(ctx); err != nil {
+		return nil, err
+	}
+
+	feeCap := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(percent))
+	feeCap.Div(feeCap, big.NewInt(100))
+	feeCap.Add(feeCap, tip)
+	return &Fees{GasTipCap: tip, GasFeeCap: feeCap, BaseFee: baseFee}, nil
+}
+
+type suggestedFees struct{}
+
+func (suggestedFees) Fees(ctx context.Context, client *Client, _ ethereum.CallMsg, _ *EIP712Meta) (*Fees, error) {
+	tip, err := client.Eth.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	baseFee, err := latestBaseFee(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	feeCap := new(big.Int).Mul(baseFee, big.NewInt(2))
+	feeCap.Add(feeCap, tip)
+	return &Fees{GasTipCap: tip, GasFeeCap: feeCap, BaseFee: baseFee}, nil
+}
+
+type estimatedFees struct{}
+
+func (estimatedFees) Fees(ctx context.Context, client *Client, msg ethereum.CallMsg, meta *EIP712Meta) (*Fees, error) {
+	fee, err := client.EstimateFee(ctx, msg, meta)
+	if err != nil {
+		return nil, err
+	}
+	if fee.MaxFeePerGas == nil || fee.MaxPriorityFeePerGas == nil {
+		return nil, errors.New("zks_estimateFee returned no fees")
+	}
+	return &Fees{GasTipCap: fee.MaxPriorityFeePerGas.ToInt(), GasFeeCap: fee.MaxFeePerGas.ToInt()}, nil
+}
+
+// latestBaseFee returns the base fee of the latest block, or the gas price on chains without one.
+func latestBaseFee(ctx context.Context, client *Client) (*big.Int, error) {
+	header, err := client.Eth.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if header.BaseFee != nil {
+		return header.BaseFee, nil
+	}
+	return client.Eth.SuggestGasPrice(ctx)
+}
+
+// WithFeeStrategy prices the transaction with s instead of the client's fee strategy.
+// WithGasTipCap and WithGasFeeCap still take precedence.
+func WithFeeStrategy(s FeeStrategy) TxOption {
+	return func(cfg *txConfig) {
+		cfg.feeStrategy = s
+	}
+}
+
+// WithDefaultFeeStrategy sets the fee strategy of the client's transactions.
+// Defaults to SuggestedFees.
+func WithDefaultFeeStrategy(s FeeStrategy) DialOption {
+	return func(cfg *dialConfig) {
+		cfg.feeStrategy = s
+	}
+}
+
+// SetFeeStrategy replaces the client's fee strategy; nil restores SuggestedFees.
+func (c *Client) SetFeeStrategy(s FeeStrategy) {
+	c.feeStrategy.set(s)
+}
+
+// FeeStrategy returns the strategy pricing the client's transactions.
+func (c *Client) FeeStrategy() FeeStrategy {
+	return c.feeStrategy.get()
+}
+
+// feeStrategyRef holds a client's fee strategy, which can be swapped at runtime.
+type feeStrategyRef struct {
+	p atomic.Pointer[FeeStrategy]
+}
+
+func (r *feeStrategyRef) set(s FeeStrategy) {
+	if s == nil {
+		r.p.Store(nil)
+		return
+	}
+	r.p.Store(&s)
+}
+
+// get returns the current strategy, or SuggestedFees if none is set.
+func (r *feeStrategyRef) get() FeeStrategy {
+	if s := r.p.Load(); s != nil {
+		return *s
+	}
+	return SuggestedFees
+}
@@ -0,0 +1,61 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestEstimatedFeesPriceTheEIP712Meta(t *testing.T) {
+	fake := NewFakeBackend().On("zks_estimateFee", map[string]string{
+		"gas_limit":                "0x5208",
+		"max_fee_per_gas":          "0x64",
+		"max_priority_fee_per_gas": "0xa",
+		"gas_per_pubdata_limit":    "0xc350",
+	})
+	client, err := NewClientFromBackend(fake, WithDefaultFeeStrategy(EstimatedFees))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paymaster := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	params, err := GeneralPaymasterParams(paymaster, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	tx, err := w.BuildAndSendTx712(context.Background(), client, &to, big.NewInt(0), nil, nil, nil,
+		WithPaymaster(params), WithBuildOnly(), WithNonce(0), WithGasLimit(21000), WithChainID(big.NewInt(2741)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.GasFeeCap().Int64() != 100 || tx.GasTipCap().Int64() != 10 {
+		t.Fatalf("fees = %s/%s, want 100/10", tx.GasFeeCap(), tx.GasTipCap())
+	}
+
+	calls := fake.CallsTo("zks_estimateFee")
+	if len(calls) != 1 {
+		t.Fatalf("zks_estimateFee called %d times, want 1", len(calls))
+	}
+	var arg struct {
+		EIP712Meta struct {
+			PaymasterParams *struct {
+				Paymaster common.Address `json:"paymaster"`
+			} `json:"paymasterParams"`
+		} `json:"eip712Meta"`
+	}
+	if err := json.Unmarshal(calls[0].Params[0], &arg); err != nil {
+		t.Fatal(err)
+	}
+	if pp := arg.EIP712Meta.PaymasterParams; pp == nil || pp.Paymaster != paymaster {
+		t.Fatalf("fee estimate sent without the paymaster: %s", calls[0].Params[0])
+	}
+}
@@ -0,0 +1,214 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// FinalityPollInterval is how often TrackFinality polls the zks_ endpoints.
+const FinalityPollInterval = 5 * time.Second
+
+// finalityPollInterval is the interval TrackFinality uses; tests shorten it.
+var finalityPollInterval = FinalityPollInterval
+
+// FinalityStage is the settlement stage of an L2 transaction.
+type FinalityStage int
+
+const (
+	StagePending   FinalityStage = iota // known to the node, not yet in a block
+	StageIncluded                       // included in an L2 block
+	StageCommitted                      // L1 batch committed on L1
+	StageProven                         // L1 batch proven on L1
+	StageExecuted                       // L1 batch executed on L1 (final)
+	StageFailed                         // transaction failed on L2
+)
+
+// String returns a readable name of the stage.
+func (s FinalityStage) String() string {
+	switch s {
+	case StagePending:
+		return "pending"
+	case StageIncluded:
+		return "included"
+	case StageCommitted:
+		return "committed"
+	case StageProven:
+		return "proven"
+	case StageExecuted:
+		return "executed"
+	case StageFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// FinalityUpdate reports a stage change of a tracked transaction.
+// L1TxHash is the L1 commit, prove or execute transaction of that stage, if any.
+// Updates with a non-nil Err report a polling error; tracking continues.
+type FinalityUpdate struct {
+	TxHash        common.Hash
+	Stage         FinalityStage
+	L1BatchNumber *uint64
+	L1TxHash      *common.Hash
+	Err           error
+}
+
+// TrackFinality reports the settlement stages of an L2 transaction on the returned channel.
+// The channel is closed once the batch is executed, the transaction fails, or ctx is done.
+func (c *Client) TrackFinality(ctx context.Context, txHash common.Hash) (<-chan FinalityUpdate, error) {
+	if txHash == (common.Hash{}) {
+		return nil, errors.New("empty transaction hash")
+	}
+
+	// One slot per stage plus room for errors so the poller rarely blocks
+	updates := make(chan FinalityUpdate, 8)
+
+	go func() {
+		defer close(updates)
+
+		ticker := time.NewTicker(finalityPollInterval)
+		defer ticker.Stop()
+
+		last := FinalityStage(-1)
+		var batch *uint64
+		for {
+			update := c.pollFinality(ctx, txHash, batch)
+			if update.L1BatchNumber != nil {
+				batch = update.L1BatchNumber
+			}
+
+			if update.Err != nil || update.Stage > last {
+				select {
+				case updates <- update:
+				case <-ctx.Done():
+					return
+				}
+				if update.Err == nil {
+					last = update.Stage
+				}
+			}
+			if last == StageExecuted || last == StageFailed {
+				return
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
+
+// WaitFinality blocks until the transaction reaches at least the given stage.
+// Returns an error if the transaction fails, ctx is done, or tracking ends first.
+func (c *Client) WaitFinality(ctx context.Context, txHash common.Hash, stage FinalityStage) (*FinalityUpdate, error) {
+	// Stop tracking once the stage is reached
+	trackCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	updates, err := c.TrackFinality(trackCtx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	var last *FinalityUpdate
+	for update := range updates {
+		if update.Err != nil {
+			continue
+		}
+		if update.Stage == StageFailed {
+			return &update, errors.New("transaction failed")
+		}
+		if update.Stage >= stage {
+			return &update, nil
+		}
+		last = &update
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if last == nil {
+		return nil, fmt.Errorf("finality tracking ended before stage %s", stage)
+	}
+	return last, fmt.Errorf("finality tracking ended at stage %s before %s", last.Stage, stage)
+}
+
+// pollFinality queries the current stage of a transaction.
+// Before the L1 batch is known it relies on zks_getTransactionDetails,
+// afterwards on zks_getL1BatchDetails for that batch.
+func (c *Client) pollFinality(ctx context.Context, txHash common.Hash, batch *uint64) FinalityUpdate {
+	update := FinalityUpdate{TxHash: txHash, L1BatchNumber: batch}
+
+	if batch == nil {
+		details, err := c.TransactionDetails(ctx, txHash)
+		if errors.Is(err, ethereum.NotFound) {
+			update.Stage = StagePending
+			return update
+		}
+		if err != nil {
+			update.Err = err
+			return update
+		}
+		switch details.Status {
+		case TxStatusPending:
+			update.Stage = StagePending
+			return update
+		case TxStatusFailed:
+			update.Stage = StageFailed
+			return update
+		}
+		update.Stage = StageIncluded
+
+		number, err := c.l1BatchNumberOf(ctx, txHash)
+		if err != nil {
+			update.Err = err
+			return update
+		}
+		if number == nil {
+			// Included in a block that is not sealed into a batch yet
+			return update
+		}
+		update.L1BatchNumber = number
+	}
+
+	details, err := c.L1BatchDetails(ctx, *update.L1BatchNumber)
+	if err != nil {
+		update.Stage = StageIncluded
+		update.Err = err
+		return update
+	}
+	switch {
+	case details.ExecuteTxHash != nil:
+		update.Stage, update.L1TxHash = StageExecuted, details.ExecuteTxHash
+	case details.ProveTxHash != nil:
+		update.Stage, update.L1TxHash = StageProven, details.ProveTxHash
+	case details.CommitTxHash != nil:
+		update.Stage, update.L1TxHash = StageCommitted, details.CommitTxHash
+	default:
+		update.Stage = StageIncluded
+	}
+	return update
+}
+
+// l1BatchNumberOf reads the zkSync-specific l1BatchNumber field of a receipt.
+// Returns nil if the receipt or its batch number is not available yet.
+func (c *Client) l1BatchNumberOf(ctx context.Context, txHash common.Hash) (*uint64, error) {
+	var receipt *struct {
+		L1BatchNumber *hexutil.Uint64 `json:"l1BatchNumber"`
+	}
+	if err := c.RpcClient.CallContext(ctx, &receipt, "eth_getTransactionReceipt", txHash); err != nil {
+		return nil, err
+	}
+	if receipt == nil || receipt.L1BatchNumber == nil {
+		return nil, nil
+	}
+	number := uint64(*receipt.L1BatchNumber)
+	return &number, nil
+}
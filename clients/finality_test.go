@@ -0,0 +1,142 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	finalityTx = common.HexToHash("0xf1")
+	commitTx   = common.HexToHash("0xc1")
+	proveTx    = common.HexToHash("0xc2")
+	executeTx  = common.HexToHash("0xc3")
+)
+
+// fastFinality makes TrackFinality poll every millisecond for the rest of the test.
+func fastFinality(t *testing.T) {
+	interval := finalityPollInterval
+	finalityPollInterval = time.Millisecond
+	t.Cleanup(func() { finalityPollInterval = interval })
+}
+
+// finalityFake scripts a transaction that is seen pending, included before its block
+// is sealed into batch 7, and then committed, proven and executed on L1.
+func finalityFake(t *testing.T) *FakeBackend {
+	t.Helper()
+	fastFinality(t)
+	return NewFakeBackend().
+		Once("zks_getTransactionDetails", map[string]any{"status": TxStatusPending}).
+		On("zks_getTransactionDetails", map[string]any{"status": TxStatusIncluded}).
+		Once("eth_getTransactionReceipt", nil).
+		On("eth_getTransactionReceipt", map[string]any{"l1BatchNumber": "0x7"}).
+		OnceError("zks_getL1BatchDetails", &FixtureError{Code: -32000, Message: "batch details unavailable"}).
+		Once("zks_getL1BatchDetails", map[string]any{"number": 7, "commitTxHash": commitTx}).
+		Once("zks_getL1BatchDetails", map[string]any{"number": 7, "commitTxHash": commitTx, "proveTxHash": proveTx}).
+		On("zks_getL1BatchDetails", map[string]any{"number": 7, "commitTxHash": commitTx, "proveTxHash": proveTx, "executeTxHash": executeTx})
+}
+
+func TestTrackFinalityReportsEachStage(t *testing.T) {
+	fake := finalityFake(t)
+	client := waitClient(t, fake)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	updates, err := client.TrackFinality(ctx, finalityTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []FinalityUpdate
+	for update := range updates {
+		got = append(got, update)
+	}
+
+	want := []struct {
+		stage FinalityStage
+		l1Tx  *common.Hash
+		err   bool
+	}{
+		{StagePending, nil, false},
+		{StageIncluded, nil, false},
+		{StageIncluded, nil, true},
+		{StageCommitted, &commitTx, false},
+		{StageProven, &proveTx, false},
+		{StageExecuted, &executeTx, false},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d updates %+v, want %d", len(got), got, len(want))
+	}
+	for i, w := range want {
+		u := got[i]
+		if u.TxHash != finalityTx || u.Stage != w.stage || (u.Err != nil) != w.err {
+			t.Errorf("update %d = %s (err %v), want %s (err %t)", i, u.Stage, u.Err, w.stage, w.err)
+		}
+		if (u.L1TxHash == nil) != (w.l1Tx == nil) || (w.l1Tx != nil && *u.L1TxHash != *w.l1Tx) {
+			t.Errorf("update %d L1 tx = %v, want %v", i, u.L1TxHash, w.l1Tx)
+		}
+		if i >= 2 && (u.L1BatchNumber == nil || *u.L1BatchNumber != 7) {
+			t.Errorf("update %d batch = %v, want 7", i, u.L1BatchNumber)
+		}
+	}
+
+	// Once the batch is known only its details are polled
+	if n := len(fake.CallsTo("zks_getTransactionDetails")); n != 3 {
+		t.Errorf("%d transaction detail polls, want 3", n)
+	}
+	for _, call := range fake.CallsTo("zks_getL1BatchDetails") {
+		if string(call.Params[0]) != "7" {
+			t.Errorf("batch details polled for %s, want 7", call.Params[0])
+		}
+	}
+}
+
+func TestWaitFinalityStopsAtTheRequestedStage(t *testing.T) {
+	fake := finalityFake(t)
+	client := waitClient(t, fake)
+
+	update, err := client.WaitFinality(context.Background(), finalityTx, StageCommitted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.Stage != StageCommitted || update.L1TxHash == nil || *update.L1TxHash != commitTx {
+		t.Fatalf("update = %+v, want committed by %s", update, commitTx)
+	}
+}
+
+func TestWaitFinalityFailedTransaction(t *testing.T) {
+	fastFinality(t)
+	client := waitClient(t, NewFakeBackend().On("zks_getTransactionDetails", map[string]any{"status": TxStatusFailed}))
+
+	update, err := client.WaitFinality(context.Background(), finalityTx, StageExecuted)
+	if err == nil || update == nil || update.Stage != StageFailed {
+		t.Fatalf("WaitFinality = %+v, %v, want a failed update and an error", update, err)
+	}
+}
+
+func TestWaitFinalityErrorsWhenTrackingEndsEarly(t *testing.T) {
+	client := waitClient(t, finalityFake(t))
+
+	// Tracking ends once the batch is executed, which never reaches StageFailed
+	update, err := client.WaitFinality(context.Background(), finalityTx, StageFailed)
+	if err == nil || !strings.Contains(err.Error(), "executed") {
+		t.Fatalf("err = %v, want tracking ended at executed", err)
+	}
+	if update == nil || update.Stage != StageExecuted {
+		t.Fatalf("update = %+v, want the executed update", update)
+	}
+}
+
+func TestWaitFinalityHonoursContext(t *testing.T) {
+	fastFinality(t)
+	client := waitClient(t, NewFakeBackend().On("zks_getTransactionDetails", map[string]any{"status": TxStatusPending}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.WaitFinality(ctx, finalityTx, StageIncluded); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
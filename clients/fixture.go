@@ -0,0 +1,291 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Fixture is a recorded sequence of JSON-RPC calls and their responses.
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded JSON-RPC call: its request and either a result or an error.
+type Interaction struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *FixtureError   `json:"error,omitempty"`
+}
+
+// FixtureError is a recorded JSON-RPC error. It keeps the original code and data on replay.
+type FixtureError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *FixtureError) Error() string {
+	return e.Message
+}
+
+// ErrorCode returns the JSON-RPC error code.
+func (e *FixtureError) ErrorCode() int {
+	return e.Code
+}
+
+// ErrorData returns the error data, or nil if none was recorded.
+func (e *FixtureError) ErrorData() interface{} {
+	if len(e.Data) == 0 {
+		return nil
+	}
+	return e.Data
+}
+
+// LoadFixture reads a fixture written by Recorder.Save.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fx Fixture
+	if err := json.Unmarshal(data, &fx); err != nil {
+		return nil, fmt.Errorf("decoding fixture %s: %w", path, err)
+	}
+	return &fx, nil
+}
+
+// Save writes the fixture to path as indented JSON.
+func (fx *Fixture) Save(path string) error {
+	data, err := json.MarshalIndent(fx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Recorder captures every JSON-RPC call a client sends, with its response.
+// Attach it with WithRecorder, run the test against a live endpoint, then Save.
+type Recorder struct {
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder creates an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// WithRecorder records the calls sent to each endpoint, below retries and the cache,
+// so the fixture holds exactly what reached the node. Calls over WebSocket and IPC are
+// recorded too; subscription notifications are not.
+func WithRecorder(rec *Recorder) DialOption {
+	return func(cfg *dialConfig) {
+		cfg.recorder = rec
+	}
+}
+
+// Fixture returns a copy of everything recorded so far.
+func (r *Recorder) Fixture() *Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Fixture{Interactions: append([]Interaction(nil), r.interactions...)}
+}
+
+// Save writes everything recorded so far to path.
+func (r *Recorder) Save(path string) error {
+	return r.Fixture().Save(path)
+}
+
+// transport returns a RoundTripper that records the calls and responses passing through next.
+func (r *Recorder) transport(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := readRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			return resp, err
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		r.record(body, respBody)
+		return resp, nil
+	})
+}
+
+// record pairs the calls of a request with the responses carrying the same IDs.
+func (r *Recorder) record(reqBody, respBody []byte) {
+	calls, _, err := parseRPCMessages(reqBody)
+	if err != nil {
+		return
+	}
+	replies, _, err := parseRPCMessages(respBody)
+	if err != nil {
+		return
+	}
+	byID := make(map[string]rpcMessage, len(replies))
+	for _, reply := range replies {
+		byID[string(reply.ID)] = reply
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, call := range calls {
+		reply, ok := byID[string(call.ID)]
+		if !ok {
+			continue
+		}
+		in := Interaction{Method: call.Method, Params: compactJSON(call.Params), Result: reply.Result}
+		if reply.Error != nil {
+			in.Error = &FixtureError{Code: reply.Error.Code, Message: reply.Error.Message, Data: reply.Error.Data}
+		}
+		r.interactions = append(r.interactions, in)
+	}
+}
+
+// ReplayMode selects how a Replayer picks the recorded response for a call.
+type ReplayMode int
+
+const (
+	// ReplayInOrder serves the interactions in recorded order and fails on any call
+	// that differs from the next recorded one.
+	ReplayInOrder ReplayMode = iota
+
+	// ReplayMatch serves the first unused interaction with the same method and params.
+	// Once all matching interactions are used, the last one is served again.
+	ReplayMatch
+)
+
+// Replayer is a Backend that serves the responses of a Fixture, without any network.
+// Pass it to NewClientFromBackend.
+type Replayer struct {
+	fixture *Fixture
+	mode    ReplayMode
+
+	mu   sync.Mutex
+	next int
+	used []bool
+}
+
+// NewReplayer creates a Replayer serving fx in the given mode.
+func NewReplayer(fx *Fixture, mode ReplayMode) *Replayer {
+	return &Replayer{fixture: fx, mode: mode, used: make([]bool, len(fx.Interactions))}
+}
+
+// Remaining returns how many recorded interactions have not been served yet.
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, used := range r.used {
+		if !used {
+			n++
+		}
+	}
+	return n
+}
+
+// CallContext serves the recorded response of the call.
+func (r *Replayer) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	params, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		params = nil
+	}
+	in, err := r.lookup(method, params)
+	if err != nil {
+		return err
+	}
+	if in.Error != nil {
+		return in.Error
+	}
+	if result == nil || len(in.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(in.Result, result)
+}
+
+// BatchCallContext serves each element as CallContext would, setting its Error.
+func (r *Replayer) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	for i := range b {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b[i].Error = r.CallContext(ctx, b[i].Result, b[i].Method, b[i].Args...)
+	}
+	return nil
+}
+
+// Close does nothing; a Replayer holds no resources.
+func (r *Replayer) Close() {}
+
+// lookup returns the interaction to serve for a call.
+func (r *Replayer) lookup(method string, params json.RawMessage) (*Interaction, error) {
+	params = compactJSON(params)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := r.fixture.Interactions
+
+	if r.mode == ReplayInOrder {
+		if r.next >= len(all) {
+			return nil, fmt.Errorf("replay: unexpected %s call after the end of the fixture", method)
+		}
+		in := &all[r.next]
+		if in.Method != method || !bytes.Equal(compactJSON(in.Params), params) {
+			return nil, fmt.Errorf("replay: got %s %s, fixture expects %s %s at call %d",
+				method, params, in.Method, in.Params, r.next)
+		}
+		r.used[r.next] = true
+		r.next++
+		return in, nil
+	}
+
+	last := -1
+	for i := range all {
+		if all[i].Method != method || !bytes.Equal(compactJSON(all[i].Params), params) {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return &all[i], nil
+		}
+		last = i
+	}
+	if last < 0 {
+		return nil, fmt.Errorf("replay: no recorded %s call with params %s", method, params)
+	}
+	return &all[last], nil
+}
+
+// compactJSON strips insignificant whitespace so recorded and replayed params compare equal.
+// Empty params and an empty array are treated alike.
+func compactJSON(raw json.RawMessage) json.RawMessage {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return raw
+	}
+	if buf.Len() == 0 || buf.String() == "[]" {
+		return nil
+	}
+	return buf.Bytes()
+}
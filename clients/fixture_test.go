@@ -0,0 +1,173 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// revertData is the error data of a call reverting with Error("no").
+const revertData = "0x08c379a0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000026e6f000000000000000000000000000000000000000000000000000000000000"
+
+// fixtureNode stands in for a node: it serves eth_chainId and eth_blockNumber,
+// and reverts every eth_call.
+func fixtureNode(t *testing.T) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		reply := rpcMessage{Version: "2.0", ID: req.ID}
+		switch req.Method {
+		case "eth_chainId":
+			reply.Result = json.RawMessage(`"0xab5"`)
+		case "eth_blockNumber":
+			reply.Result = json.RawMessage(`"0x10"`)
+		case "eth_call":
+			reply.Error = &rpcError{Code: 3, Message: "execution reverted: no", Data: json.RawMessage(`"` + revertData + `"`)}
+		default:
+			reply.Error = &rpcError{Code: -32601, Message: "method not found"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(reply)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// fixtureSession makes the calls recorded and replayed by the fixture tests.
+func fixtureSession(t *testing.T, client *Client) {
+	t.Helper()
+	ctx := context.Background()
+	to := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+
+	id, err := client.Eth.ChainID(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Cmp(big.NewInt(2741)) != 0 {
+		t.Fatalf("chain ID = %s, want 2741", id)
+	}
+	block, err := client.Eth.BlockNumber(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block != 16 {
+		t.Fatalf("block = %d, want 16", block)
+	}
+
+	_, err = client.Eth.CallContract(ctx, ethereum.CallMsg{To: &to}, nil)
+	var coded rpc.Error
+	if !errors.As(err, &coded) || coded.ErrorCode() != 3 {
+		t.Fatalf("call error = %v, want code 3", err)
+	}
+	var withData rpc.DataError
+	if !errors.As(err, &withData) || withData.ErrorData() != revertData {
+		t.Fatalf("call error data = %v, want the revert data", err)
+	}
+}
+
+func TestRecordSaveLoadReplay(t *testing.T) {
+	rec := NewRecorder()
+	live, err := Dial(fixtureNode(t), WithRecorder(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixtureSession(t, live)
+	live.Close()
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	fx, err := LoadFixture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(fx.Interactions); n != 3 {
+		t.Fatalf("recorded %d interactions, want 3", n)
+	}
+
+	for _, mode := range []ReplayMode{ReplayInOrder, ReplayMatch} {
+		replayer := NewReplayer(fx, mode)
+		client, err := NewClientFromBackend(replayer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fixtureSession(t, client)
+		client.Close()
+		if n := replayer.Remaining(); n != 0 {
+			t.Fatalf("mode %d: %d interactions left", mode, n)
+		}
+	}
+}
+
+func TestReplayModes(t *testing.T) {
+	fx := &Fixture{Interactions: []Interaction{
+		{Method: "eth_blockNumber", Result: json.RawMessage(`"0x1"`)},
+		{Method: "eth_chainId", Result: json.RawMessage(`"0xab5"`)},
+		{Method: "eth_blockNumber", Result: json.RawMessage(`"0x2"`)},
+	}}
+	ctx := context.Background()
+
+	inOrder, err := NewClientFromBackend(NewReplayer(fx, ReplayInOrder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inOrder.Close()
+	if _, err := inOrder.Eth.ChainID(ctx); err == nil {
+		t.Fatal("ReplayInOrder served a call out of order")
+	}
+
+	match, err := NewClientFromBackend(NewReplayer(fx, ReplayMatch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer match.Close()
+	if id, err := match.Eth.ChainID(ctx); err != nil || id.Uint64() != 2741 {
+		t.Fatalf("chain ID = %v, %v; want 2741", id, err)
+	}
+	// Matching calls are served in recorded order, then the last one again
+	for _, want := range []uint64{1, 2, 2} {
+		if n, err := match.Eth.BlockNumber(ctx); err != nil || n != want {
+			t.Fatalf("block = %d, %v; want %d", n, err, want)
+		}
+	}
+	if _, err := match.Eth.SuggestGasPrice(ctx); err == nil {
+		t.Fatal("ReplayMatch served an unrecorded call")
+	}
+}
+
+func TestRecorderRecordsNonHTTPCalls(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &chainIDService{}); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	rec := NewRecorder()
+	client, err := NewClientFromRPC(rpc.DialInProc(server), WithRecorder(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if _, err := client.Eth.ChainID(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	got := rec.Fixture().Interactions
+	if len(got) != 1 || got[0].Method != "eth_chainId" || string(got[0].Result) != `"0xab5"` {
+		t.Fatalf("recorded %+v, want one eth_chainId call", got)
+	}
+}
@@ -0,0 +1,184 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBlockAge is how old the latest block may be before the node is reported unhealthy.
+const DefaultMaxBlockAge = time.Minute
+
+// Health checks, used as keys of HealthReport.Errors.
+const (
+	HealthCheckBlock   = "block"
+	HealthCheckSync    = "sync"
+	HealthCheckChainID = "chainId"
+	HealthCheckPeers   = "peers"
+	HealthCheckL1Batch = "l1Batch"
+)
+
+// HealthReport is a snapshot of an endpoint's state, ready to be serialized to JSON.
+// Durations are serialized in nanoseconds.
+type HealthReport struct {
+	// Healthy is true when the block, sync and chain ID checks pass,
+	// the node is not syncing and its latest block is recent enough.
+	Healthy   bool          `json:"healthy"`
+	CheckedAt time.Time     `json:"checkedAt"`
+	Latency   time.Duration `json:"latencyNs"` // eth_blockNumber round trip
+
+	Syncing      bool   `json:"syncing"`
+	CurrentBlock uint64 `json:"currentBlock,omitempty"` // sync progress, while syncing
+	HighestBlock uint64 `json:"highestBlock,omitempty"` // sync target, while syncing
+
+	LatestBlock uint64        `json:"latestBlock"`
+	BlockTime   time.Time     `json:"blockTime"`
+	BlockAge    time.Duration `json:"blockAgeNs"`
+
+	ChainID         *big.Int `json:"chainId,omitempty"`
+	ExpectedChainID *big.Int `json:"expectedChainId,omitempty"`
+	ChainIDMatch    bool     `json:"chainIdMatch"`
+
+	// PeerCount is nil when the node does not serve net_peerCount.
+	PeerCount *uint64 `json:"peerCount,omitempty"`
+
+	// L1BatchNumber is the latest sealed L1 batch and L1BatchAge how long ago it was sealed.
+	// Both are zero when the node does not serve the zks_ namespace.
+	L1BatchNumber uint64        `json:"l1BatchNumber,omitempty"`
+	L1BatchAge    time.Duration `json:"l1BatchAgeNs,omitempty"`
+
+	// Errors maps each failed check to its error. Peer and L1 batch failures are
+	// reported but do not make the node unhealthy, since not every node serves them.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// HealthOption customizes a health check.
+type HealthOption func(*healthConfig)
+
+type healthConfig struct {
+	maxBlockAge time.Duration
+}
+
+// WithMaxBlockAge sets how old the latest block may be; zero disables the check.
+// Defaults to DefaultMaxBlockAge.
+func WithMaxBlockAge(d time.Duration) HealthOption {
+	return func(cfg *healthConfig) {
+		cfg.maxBlockAge = d
+	}
+}
+
+// Health checks the endpoint's latest block, sync status, chain ID, peers and L1 batches.
+// Failed checks are recorded in the report; the returned error is only ctx's error.
+// Every check asks the node, bypassing the cached chain ID and the response cache.
+func (c *Client) Health(ctx context.Context, opts ...HealthOption) (*HealthReport, error) {
+	cfg := &healthConfig{maxBlockAge: DefaultMaxBlockAge}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(cfg)
+		}
+	}
+
+	report := &HealthReport{CheckedAt: time.Now()}
+	if c.chainID.expected != nil {
+		report.ExpectedChainID = new(big.Int).Set(c.chainID.expected)
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = make(map[string]string)
+	)
+	fail := func(check string, err error) {
+		mu.Lock()
+		errs[check] = err.Error()
+		mu.Unlock()
+	}
+	run := func(check string, fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(); err != nil {
+				fail(check, err)
+			}
+		}()
+	}
+
+	run(HealthCheckBlock, func() error {
+		start := time.Now()
+		number, err := c.Eth.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		report.Latency = time.Since(start)
+		report.LatestBlock = number
+
+		header, err := c.Eth.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return err
+		}
+		report.BlockTime = time.Unix(int64(header.Time), 0).UTC()
+		report.BlockAge = report.CheckedAt.Sub(report.BlockTime)
+		return nil
+	})
+	run(HealthCheckSync, func() error {
+		progress, err := c.Eth.SyncProgress(ctx)
+		if err != nil {
+			return err
+		}
+		if progress != nil {
+			report.Syncing = true
+			report.CurrentBlock = progress.CurrentBlock
+			report.HighestBlock = progress.HighestBlock
+		}
+		return nil
+	})
+	run(HealthCheckChainID, func() error {
+		id, err := c.Eth.ChainID(skipCache(ctx))
+		if err != nil {
+			return err
+		}
+		report.ChainID = id
+		report.ChainIDMatch = report.ExpectedChainID == nil || id.Cmp(report.ExpectedChainID) == 0
+		if !report.ChainIDMatch {
+			return fmt.Errorf("%w: node reports %s, expected %s", ErrChainIDMismatch, id, report.ExpectedChainID)
+		}
+		return nil
+	})
+	run(HealthCheckPeers, func() error {
+		peers, err := c.Eth.PeerCount(ctx)
+		if err != nil {
+			return err
+		}
+		report.PeerCount = &peers
+		return nil
+	})
+	run(HealthCheckL1Batch, func() error {
+		number, err := c.L1BatchNumber(ctx)
+		if err != nil {
+			return err
+		}
+		report.L1BatchNumber = number
+		details, err := c.L1BatchDetails(ctx, number)
+		if err != nil {
+			return err
+		}
+		report.L1BatchAge = report.CheckedAt.Sub(time.Unix(int64(details.Timestamp), 0))
+		return nil
+	})
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		report.Errors = errs
+	}
+
+	_, blockErr := errs[HealthCheckBlock]
+	_, syncErr := errs[HealthCheckSync]
+	report.Healthy = !blockErr && !syncErr && report.ChainIDMatch && !report.Syncing &&
+		(cfg.maxBlockAge == 0 || report.BlockAge <= cfg.maxBlockAge)
+	return report, nil
+}
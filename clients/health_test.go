@@ -0,0 +1,36 @@
+package clients
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHealthAsksForChainIDEveryCheck(t *testing.T) {
+	fake := NewFakeBackend().Once("eth_chainId", "0xab5").On("eth_chainId", "0x1")
+	// Dialing verifies and caches 2741; the endpoint then switches chains
+	client, err := NewClientFromBackend(fake, WithCache(CacheConfig{}), WithExpectedChainID(2741))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	report, err := client.Health(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.ChainID == nil || report.ChainID.Int64() != 1 || report.ChainIDMatch {
+		t.Fatalf("report chain ID = %v (match %v), want 1 and a mismatch", report.ChainID, report.ChainIDMatch)
+	}
+	if _, ok := report.Errors[HealthCheckChainID]; !ok {
+		t.Fatalf("no chain ID error in %v", report.Errors)
+	}
+	if n := len(fake.CallsTo("eth_chainId")); n != 2 {
+		t.Fatalf("eth_chainId called %d times, want 2", n)
+	}
+	if _, err := client.Health(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(fake.CallsTo("eth_chainId")); n != 3 {
+		t.Fatalf("eth_chainId called %d times, want 3", n)
+	}
+}
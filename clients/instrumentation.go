@@ -0,0 +1,158 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// Instrumentation observes the SDK's RPC calls, transaction sends and subscription events.
+// Implementations must be safe for concurrent use.
+type Instrumentation interface {
+	// StartRPC is called before a JSON-RPC call is sent; the returned function is called
+	// once with the call's outcome. Batches report one call per method.
+	StartRPC(ctx context.Context, method string) (context.Context, func(err error))
+
+	// StartTxSend is called before a signed transaction is submitted; the returned
+	// function is called once with the send's outcome.
+	StartTxSend(ctx context.Context, tx Tx) (context.Context, func(err error))
+
+	// SubscriptionEvent is called for every event a watcher or SubscriptionManager receives,
+	// with a non-nil err when handling fails or the subscription breaks. kind names the
+	// stream, e.g. "newHeads", "logs", "pendingTxs", "contractEvent" or "erc20.Transfer".
+	SubscriptionEvent(ctx context.Context, kind string, err error)
+}
+
+// WithInstrumentation reports the client's activity to inst.
+// RPC calls are observed on every transport; notifications pushed over a native subscription
+// are not calls and are reported through SubscriptionEvent by the watchers instead.
+func WithInstrumentation(inst Instrumentation) DialOption {
+	return func(cfg *dialConfig) {
+		cfg.inst = inst
+	}
+}
+
+// MultiInstrumentation fans every callback out to each of insts in order.
+func MultiInstrumentation(insts ...Instrumentation) Instrumentation {
+	return multiInstrumentation(insts)
+}
+
+type multiInstrumentation []Instrumentation
+
+func (m multiInstrumentation) StartRPC(ctx context.Context, method string) (context.Context, func(error)) {
+	ends := make([]func(error), len(m))
+	for i, inst := range m {
+		ctx, ends[i] = inst.StartRPC(ctx, method)
+	}
+	return ctx, func(err error) {
+		for i := len(ends) - 1; i >= 0; i-- {
+			ends[i](err)
+		}
+	}
+}
+
+func (m multiInstrumentation) StartTxSend(ctx context.Context, tx Tx) (context.Context, func(error)) {
+	ends := make([]func(error), len(m))
+	for i, inst := range m {
+		ctx, ends[i] = inst.StartTxSend(ctx, tx)
+	}
+	return ctx, func(err error) {
+		for i := len(ends) - 1; i >= 0; i-- {
+			ends[i](err)
+		}
+	}
+}
+
+func (m multiInstrumentation) SubscriptionEvent(ctx context.Context, kind string, err error) {
+	for _, inst := range m {
+		inst.SubscriptionEvent(ctx, kind, err)
+	}
+}
+
+// nopInstrumentation is used when the client has no instrumentation.
+type nopInstrumentation struct{}
+
+func (nopInstrumentation) StartRPC(ctx context.Context, _ string) (context.Context, func(error)) {
+	return ctx, func(error) {}
+}
+
+func (nopInstrumentation) StartTxSend(ctx context.Context, _ Tx) (context.Context, func(error)) {
+	return ctx, func(error) {}
+}
+
+func (nopInstrumentation) SubscriptionEvent(context.Context, string, error) {}
+
+// instrumentTransport returns a RoundTripper that reports every JSON-RPC call to inst,
+// including per-call errors inside a successful HTTP response.
+func instrumentTransport(next http.RoundTripper, inst Instrumentation) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := readRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+		reqs, _, err := parseRPCMessages(body)
+		if err != nil {
+			return next.RoundTrip(req)
+		}
+
+		// A single call's context carries its span to the node; a batch keeps the caller's
+		ctx := req.Context()
+		ends := make([]func(error), len(reqs))
+		for i, m := range reqs {
+			callCtx, end := inst.StartRPC(req.Context(), m.Method)
+			if len(reqs) == 1 {
+				ctx = callCtx
+			}
+			ends[i] = end
+		}
+		req = req.Clone(ctx)
+		injectTraceParent(req)
+
+		resp, err := next.RoundTrip(req)
+		if err == nil && resp.StatusCode != http.StatusOK {
+			statusErr := errors.New(resp.Status)
+			for _, end := range ends {
+				end(statusErr)
+			}
+			return resp, nil
+		}
+		if err != nil {
+			for _, end := range ends {
+				end(err)
+			}
+			return nil, err
+		}
+
+		respBody, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		if readErr != nil {
+			for _, end := range ends {
+				end(readErr)
+			}
+			return resp, nil
+		}
+		errs := make(map[string]error)
+		if resps, _, err := parseRPCMessages(respBody); err == nil {
+			for _, m := range resps {
+				if m.Error != nil {
+					errs[string(m.ID)] = m.Error
+				}
+			}
+		}
+		for i, m := range reqs {
+			ends[i](errs[string(m.ID)])
+		}
+		return resp, nil
+	})
+}
+
+// instrumentation returns the client's instrumentation, never nil.
+func (c *Client) instrumentation() Instrumentation {
+	if c.inst == nil {
+		return nopInstrumentation{}
+	}
+	return c.inst
+}
@@ -0,0 +1,146 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// outcome is one operation observed by an outcomeRecorder, with its error.
+type outcome struct {
+	name string
+	err  error
+}
+
+// outcomeRecorder is an Instrumentation that records every finished call, send and event.
+type outcomeRecorder struct {
+	mu       sync.Mutex
+	outcomes []outcome
+}
+
+func (r *outcomeRecorder) add(name string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outcomes = append(r.outcomes, outcome{name, err})
+}
+
+func (r *outcomeRecorder) StartRPC(ctx context.Context, method string) (context.Context, func(error)) {
+	return ctx, func(err error) { r.add(method, err) }
+}
+
+func (r *outcomeRecorder) StartTxSend(ctx context.Context, tx Tx) (context.Context, func(error)) {
+	return ctx, func(err error) { r.add("send", err) }
+}
+
+func (r *outcomeRecorder) SubscriptionEvent(_ context.Context, kind string, err error) {
+	r.add("event "+kind, err)
+}
+
+func (r *outcomeRecorder) get() []outcome {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]outcome(nil), r.outcomes...)
+}
+
+func TestInstrumentationObservesEachBatchCall(t *testing.T) {
+	fake := NewFakeBackend().
+		On("eth_chainId", "0xab5").
+		OnError("eth_call", &FixtureError{Code: 3, Message: "execution reverted"})
+	rec := &outcomeRecorder{}
+	client, err := NewClientFromBackend(fake, WithInstrumentation(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	to := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	batch := []rpc.BatchElem{
+		{Method: "eth_chainId", Result: new(string)},
+		{Method: "eth_call", Args: []interface{}{map[string]interface{}{"to": to}, "latest"}, Result: new(string)},
+	}
+	if err := client.RpcClient.BatchCallContext(context.Background(), batch); err != nil {
+		t.Fatal(err)
+	}
+
+	got := rec.get()
+	if len(got) != 2 || got[0].name != "eth_chainId" || got[0].err != nil || got[1].name != "eth_call" || got[1].err == nil {
+		t.Fatalf("observed %+v, want eth_chainId succeeding and eth_call failing", got)
+	}
+}
+
+func TestInstrumentationObservesHTTPErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	rec := &outcomeRecorder{}
+	client, err := Dial(srv.URL, WithInstrumentation(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if _, err := client.Eth.BlockNumber(context.Background()); err == nil {
+		t.Fatal("want an error")
+	}
+	got := rec.get()
+	if len(got) != 1 || got[0].name != "eth_blockNumber" || got[0].err == nil {
+		t.Fatalf("observed %+v, want a failed eth_blockNumber", got)
+	}
+}
+
+func TestInstrumentationObservesTxSends(t *testing.T) {
+	tx, _ := signedRawTx(t)
+	fake := NewFakeBackend().
+		Once("eth_sendRawTransaction", tx.Hash()).
+		OnceError("eth_sendRawTransaction", errors.New("nonce too low"))
+	rec := &outcomeRecorder{}
+	client, err := NewClientFromBackend(fake, WithInstrumentation(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if err := client.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendTransaction(context.Background(), tx); err == nil {
+		t.Fatal("second send: want an error")
+	}
+
+	got := rec.get()
+	if len(got) != 4 {
+		t.Fatalf("observed %+v, want two calls and two sends", got)
+	}
+	for i, want := range []outcome{{"eth_sendRawTransaction", nil}, {"send", nil}} {
+		if got[i].name != want.name || got[i].err != nil {
+			t.Fatalf("observed %+v, want %s succeeding at %d", got, want.name, i)
+		}
+		if got[i+2].name != want.name || got[i+2].err == nil {
+			t.Fatalf("observed %+v, want %s failing at %d", got, want.name, i+2)
+		}
+	}
+}
+
+func TestMultiInstrumentationNests(t *testing.T) {
+	var order []string
+	nest := func(name string) Instrumentation {
+		return NewTracer(func(Span) { order = append(order, "end "+name) })
+	}
+	multi := MultiInstrumentation(nest("a"), nest("b"))
+
+	ctx, end := multi.StartRPC(context.Background(), "eth_chainId")
+	if _, ok := TraceParent(ctx); !ok {
+		t.Fatal("the returned context lacks the inner span")
+	}
+	end(nil)
+	if len(order) != 2 || order[0] != "end b" || order[1] != "end a" {
+		t.Fatalf("ends = %v, want b then a", order)
+	}
+}
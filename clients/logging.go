@@ -0,0 +1,53 @@
+package clients
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// Structured fields attached to the SDK's log records.
+const (
+	LogKeyKind     = "kind"     // subscription kind, e.g. "logs" or "erc20.Transfer"
+	LogKeyContract = "contract" // contract address(es) being watched
+	LogKeyError    = "error"    // the error that caused the record
+	LogKeyAttempt  = "attempt"  // 1-based attempt number of a retried operation
+)
+
+// WithLogger sends the client's internal messages to logger.
+// Defaults to slog.Default().
+func WithLogger(logger *slog.Logger) DialOption {
+	return func(cfg *dialConfig) {
+		cfg.logger.set(logger)
+	}
+}
+
+// SetLogger replaces the client's logger, including for running watchers.
+// A nil logger restores slog.Default().
+func (c *Client) SetLogger(logger *slog.Logger) {
+	c.logger.set(logger)
+}
+
+// Logger returns the logger the client emits its internal messages to.
+func (c *Client) Logger() *slog.Logger {
+	return c.logger.get()
+}
+
+// loggerRef is a logger shared by a client and its transports that can be swapped at runtime.
+type loggerRef struct {
+	p atomic.Pointer[slog.Logger]
+}
+
+func (r *loggerRef) set(logger *slog.Logger) {
+	r.p.Store(logger)
+}
+
+// get returns the current logger, or slog.Default() if none is set.
+func (r *loggerRef) get() *slog.Logger {
+	if r == nil {
+		return slog.Default()
+	}
+	if l := r.p.Load(); l != nil {
+		return l
+	}
+	return slog.Default()
+}
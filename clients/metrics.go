@@ -0,0 +1,223 @@
+package clients
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the histogram bounds, in seconds, used by PrometheusMetrics.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PrometheusMetrics is an Instrumentation that aggregates counters and latency histograms
+// and serves them in the Prometheus text exposition format.
+//
+// Exported series, prefixed with the namespace:
+//
+//	_rpc_requests_total{method,result}          counter
+//	_rpc_request_duration_seconds{method}       histogram
+//	_tx_sends_total{type,result}                counter
+//	_tx_send_duration_seconds                   histogram
+//	_subscription_events_total{kind,result}     counter
+//
+// result is "success" or "error".
+type PrometheusMetrics struct {
+	namespace string
+
+	mu        sync.Mutex
+	rpcCount  map[[2]string]uint64
+	rpcTime   map[string]*histogram
+	txCount   map[[2]string]uint64
+	txTime    *histogram
+	subEvents map[[2]string]uint64
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// NewPrometheusMetrics creates an exporter whose series are prefixed with namespace,
+// "abstract" if empty. Serve it with http.Handle("/metrics", m).
+func NewPrometheusMetrics(namespace string) *PrometheusMetrics {
+	if namespace == "" {
+		namespace = "abstract"
+	}
+	return &PrometheusMetrics{
+		namespace: namespace,
+		rpcCount:  make(map[[2]string]uint64),
+		rpcTime:   make(map[string]*histogram),
+		txCount:   make(map[[2]string]uint64),
+		txTime:    newHistogram(),
+		subEvents: make(map[[2]string]uint64),
+	}
+}
+
+// StartRPC times the call and counts its outcome per method.
+func (m *PrometheusMetrics) StartRPC(ctx context.Context, method string) (context.Context, func(error)) {
+	start := time.Now()
+	return ctx, func(err error) {
+		elapsed := time.Since(start).Seconds()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.rpcCount[[2]string{method, resultLabel(err)}]++
+		h, ok := m.rpcTime[method]
+		if !ok {
+			h = newHistogram()
+			m.rpcTime[method] = h
+		}
+		h.observe(elapsed)
+	}
+}
+
+// StartTxSend times the send and counts its outcome per transaction type.
+func (m *PrometheusMetrics) StartTxSend(ctx context.Context, tx Tx) (context.Context, func(error)) {
+	start := time.Now()
+	txType := strconv.Itoa(int(tx.Type()))
+	return ctx, func(err error) {
+		elapsed := time.Since(start).Seconds()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.txCount[[2]string{txType, resultLabel(err)}]++
+		m.txTime.observe(elapsed)
+	}
+}
+
+// SubscriptionEvent counts the event per subscription kind.
+func (m *PrometheusMetrics) SubscriptionEvent(_ context.Context, kind string, err error) {
+	m.mu.Lock()
+	m.subEvents[[2]string{kind, resultLabel(err)}]++
+	m.mu.Unlock()
+}
+
+// ServeHTTP writes the current metrics in the Prometheus text format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the current metrics in the Prometheus text format.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+	ns := m.namespace
+
+	writeCounter(cw, ns+"_rpc_requests_total", "JSON-RPC calls by method and result.", []string{"method", "result"}, m.rpcCount)
+
+	name := ns + "_rpc_request_duration_seconds"
+	fmt.Fprintf(cw, "# HELP %s JSON-RPC call latency by method.\n# TYPE %s histogram\n", name, name)
+	methods := make([]string, 0, len(m.rpcTime))
+	for method := range m.rpcTime {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		m.rpcTime[method].write(cw, name, `method="`+escapeLabel(method)+`"`)
+	}
+
+	writeCounter(cw, ns+"_tx_sends_total", "Transaction sends by type and result.", []string{"type", "result"}, m.txCount)
+
+	name = ns + "_tx_send_duration_seconds"
+	fmt.Fprintf(cw, "# HELP %s Transaction send latency.\n# TYPE %s histogram\n", name, name)
+	m.txTime.write(cw, name, "")
+
+	writeCounter(cw, ns+"_subscription_events_total", "Subscription events by kind and result.", []string{"kind", "result"}, m.subEvents)
+
+	if err := bw.Flush(); err != nil {
+		return cw.n, err
+	}
+	return cw.n, cw.err
+}
+
+// writeCounter writes a counter family with two labels, sorted by label values.
+func writeCounter(w io.Writer, name, help string, labels []string, values map[[2]string]uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	keys := make([][2]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s{%s=\"%s\",%s=\"%s\"} %d\n", name, labels[0], escapeLabel(k[0]), labels[1], escapeLabel(k[1]), values[k])
+	}
+}
+
+func newHistogram() *histogram {
+	return &histogram{counts: make([]uint64, len(DefaultLatencyBuckets))}
+}
+
+func (h *histogram) observe(v float64) {
+	h.sum += v
+	h.count++
+	for i, bound := range DefaultLatencyBuckets {
+		if v <= bound {
+			h.counts[i]++
+			return
+		}
+	}
+}
+
+// write emits the cumulative buckets, sum and count; labels is an optional label list.
+func (h *histogram) write(w io.Writer, name, labels string) {
+	sep := ""
+	if labels != "" {
+		sep = ","
+	}
+	var cumulative uint64
+	for i, bound := range DefaultLatencyBuckets {
+		cumulative += h.counts[i]
+		fmt.Fprintf(w, "%s_bucket{%s%sle=\"%s\"} %d\n", name, labels, sep, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, sep, h.count)
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, labels, h.count)
+}
+
+func resultLabel(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+// countingWriter tracks bytes written and the first error for WriteTo.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
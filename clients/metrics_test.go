@@ -0,0 +1,48 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusMetricsExposition(t *testing.T) {
+	m := NewPrometheusMetrics("test")
+	ctx := context.Background()
+	tx, _ := signedRawTx(t)
+
+	_, end := m.StartRPC(ctx, "eth_call")
+	end(nil)
+	_, end = m.StartRPC(ctx, "eth_call")
+	end(errors.New("reverted"))
+	_, end = m.StartTxSend(ctx, tx)
+	end(nil)
+	m.SubscriptionEvent(ctx, `erc20."Transfer"`, nil)
+	m.SubscriptionEvent(ctx, "logs", errors.New("connection reset"))
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	out := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE test_rpc_requests_total counter\n",
+		`test_rpc_requests_total{method="eth_call",result="error"} 1` + "\n",
+		`test_rpc_requests_total{method="eth_call",result="success"} 1` + "\n",
+		"# TYPE test_rpc_request_duration_seconds histogram\n",
+		`test_rpc_request_duration_seconds_bucket{method="eth_call",le="+Inf"} 2` + "\n",
+		`test_rpc_request_duration_seconds_count{method="eth_call"} 2` + "\n",
+		`test_tx_sends_total{type="0",result="success"} 1` + "\n",
+		`test_tx_send_duration_seconds_bucket{le="+Inf"} 1` + "\n",
+		"test_tx_send_duration_seconds_count 1\n",
+		`test_subscription_events_total{kind="erc20.\"Transfer\"",result="success"} 1` + "\n",
+		`test_subscription_events_total{kind="logs",result="error"} 1` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("exposition lacks %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// BootloaderAddress is the ZK Stack system contract that executes blocks.
+	BootloaderAddress = common.HexToAddress("0x0000000000000000000000000000000000008001")
+
+	// KnownCodesStorageAddress is the ZK Stack system contract that records published bytecode hashes.
+	KnownCodesStorageAddress = common.HexToAddress("0x0000000000000000000000000000000000008004")
+
+	// L1MessengerAddress is the ZK Stack system contract that sends messages to L1.
+	L1MessengerAddress = common.HexToAddress("0x0000000000000000000000000000000000008008")
+
+	// L2BaseTokenAddress is the ZK Stack system contract holding base token (ETH) balances.
+	L2BaseTokenAddress = common.HexToAddress("0x000000000000000000000000000000000000800a")
+
+	// SystemContextAddress is the ZK Stack system contract exposing chain and block context.
+	SystemContextAddress = common.HexToAddress("0x000000000000000000000000000000000000800b")
+)
+
+// SystemContracts lists the well-known ZK Stack system contracts of a network.
+type SystemContracts struct {
+	Bootloader        common.Address
+	NonceHolder       common.Address
+	KnownCodesStorage common.Address
+	ContractDeployer  common.Address
+	L1Messenger       common.Address
+	L2BaseToken       common.Address
+	SystemContext     common.Address
+}
+
+// ZKStackSystemContracts are the system contract addresses shared by every ZK Stack chain.
+var ZKStackSystemContracts = SystemContracts{
+	Bootloader:        BootloaderAddress,
+	NonceHolder:       NonceHolderAddress,
+	KnownCodesStorage: KnownCodesStorageAddress,
+	ContractDeployer:  ContractDeployerAddress,
+	L1Messenger:       L1MessengerAddress,
+	L2BaseToken:       L2BaseTokenAddress,
+	SystemContext:     SystemContextAddress,
+}
+
+// Network describes a chain the SDK can connect to.
+type Network struct {
+	Name        string
+	ChainID     uint64
+	HTTPURL     string // default JSON-RPC endpoint
+	WSURL       string // default WebSocket endpoint; optional
+	ExplorerURL string // block explorer; optional
+	L1ChainID   uint64 // settlement layer chain ID
+
+	System SystemContracts
+
+	// Bridges holds the bridge addresses when they are known ahead of time. Nil for the
+	// built-in presets; Client.BridgeContracts then asks the node on first use.
+	Bridges *BridgeContracts
+}
+
+// TxURL returns the explorer page of a transaction, or "" if the network has no explorer.
+func (n Network) TxURL(hash common.Hash) string {
+	if n.ExplorerURL == "" {
+		return ""
+	}
+	return strings.TrimRight(n.ExplorerURL, "/") + "/tx/" + hash.Hex()
+}
+
+// AddressURL returns the explorer page of an account, or "" if the network has no explorer.
+func (n Network) AddressURL(addr common.Address) string {
+	if n.ExplorerURL == "" {
+		return ""
+	}
+	return strings.TrimRight(n.ExplorerURL, "/") + "/address/" + addr.Hex()
+}
+
+var (
+	// AbstractMainnet is the Abstract mainnet, settling on Ethereum mainnet.
+	AbstractMainnet = Network{
+		Name:        "abstract-mainnet",
+		ChainID:     2741,
+		HTTPURL:     "https://api.mainnet.abs.xyz",
+		WSURL:       "wss://api.mainnet.abs.xyz/ws",
+		ExplorerURL: "https://abscan.org",
+		L1ChainID:   1,
+		System:      ZKStackSystemContracts,
+	}
+
+	// AbstractTestnet is the Abstract testnet, settling on Ethereum Sepolia.
+	AbstractTestnet = Network{
+		Name:        "abstract-testnet",
+		ChainID:     11124,
+		HTTPURL:     "https://api.testnet.abs.xyz",
+		WSURL:       "wss://api.testnet.abs.xyz/ws",
+		ExplorerURL: "https://sepolia.abscan.org",
+		L1ChainID:   11155111,
+		System:      ZKStackSystemContracts,
+	}
+)
+
+var networks = struct {
+	sync.RWMutex
+	byID map[uint64]Network
+}{
+	byID: map[uint64]Network{
+		AbstractMainnet.ChainID: AbstractMainnet,
+		AbstractTestnet.ChainID: AbstractTestnet,
+	},
+}
+
+// RegisterNetwork adds a custom network, such as a local ZK Stack dev node, to the registry.
+// A network registered under an existing chain ID replaces it.
+func RegisterNetwork(n Network) error {
+	if n.ChainID == 0 {
+		return errors.New("network chain ID is required")
+	}
+	if n.Name == "" {
+		return errors.New("network name is required")
+	}
+	if n.HTTPURL == "" && n.WSURL == "" {
+		return fmt.Errorf("network %s has no RPC URL", n.Name)
+	}
+
+	networks.Lock()
+	defer networks.Unlock()
+	for id, existing := range networks.byID {
+		if id != n.ChainID && strings.EqualFold(existing.Name, n.Name) {
+			return fmt.Errorf("network name %q is already used by chain %d", n.Name, id)
+		}
+	}
+	networks.byID[n.ChainID] = n
+	return nil
+}
+
+// LookupNetwork returns the registered network with the given chain ID.
+func LookupNetwork(chainID uint64) (Network, bool) {
+	networks.RLock()
+	defer networks.RUnlock()
+	n, ok := networks.byID[chainID]
+	return n, ok
+}
+
+// NetworkByName returns the registered network with the given name, ignoring case.
+func NetworkByName(name string) (Network, bool) {
+	networks.RLock()
+	defer networks.RUnlock()
+	for _, n := range networks.byID {
+		if strings.EqualFold(n.Name, name) {
+			return n, true
+		}
+	}
+	return Network{}, false
+}
+
+// Networks returns every registered network, ordered by chain ID.
+func Networks() []Network {
+	networks.RLock()
+	defer networks.RUnlock()
+	out := make([]Network, 0, len(networks.byID))
+	for _, n := range networks.byID {
+		out = append(out, n)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ChainID < out[j].ChainID })
+	return out
+}
+
+// DialNetwork connects to a network's default endpoints and verifies the node's chain ID.
+// Calls go over HTTP and subscriptions over WebSocket; if the network has no WS URL,
+// or it cannot be reached, subscriptions are polled over HTTP instead.
+func DialNetwork(n Network, opts ...DialOption) (*Client, error) {
+	// Verify the chain first; an explicit WithExpectedChainID in opts takes precedence
+	opts = append([]DialOption{WithExpectedChainID(n.ChainID)}, opts...)
+
+	var (
+		c   *Client
+		err error
+	)
+	switch {
+	case n.HTTPURL == "" && n.WSURL == "":
+		return nil, fmt.Errorf("network %s has no RPC URL", n.Name)
+	case n.HTTPURL == "":
+		c, err = Dial(n.WSURL, opts...)
+	case n.WSURL == "":
+		c, err = Dial(n.HTTPURL, opts...)
+	default:
+		c, err = DialPaired(n.HTTPURL, n.WSURL, opts...)
+		if err != nil {
+			var httpErr error
+			if c, httpErr = Dial(n.HTTPURL, opts...); httpErr != nil {
+				return nil, err
+			}
+			c.Logger().WarnContext(context.Background(), "WebSocket endpoint unreachable, polling subscriptions over HTTP",
+				"network", n.Name, "url", n.WSURL, LogKeyError, err)
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.network = &n
+	return c, nil
+}
+
+// Network returns the network the client was dialed with, or nil if it was not dialed
+// with DialNetwork.
+func (c *Client) Network() *Network {
+	return c.network
+}
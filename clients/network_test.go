@@ -0,0 +1,97 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// networkStub serves eth_chainId and zks_getBridgeContracts, or bridges as null when nil.
+// It counts the zks_getBridgeContracts calls in bridgeCalls.
+func networkStub(t *testing.T, chainID string, bridges *BridgeContracts, bridgeCalls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		reply := rpcMessage{Version: "2.0", ID: req.ID}
+		switch req.Method {
+		case "eth_chainId":
+			reply.Result, _ = json.Marshal(chainID)
+		case "zks_getBridgeContracts":
+			bridgeCalls.Add(1)
+			reply.Result, _ = json.Marshal(bridges)
+		default:
+			reply.Error = &rpcError{Code: -32601, Message: "method not found"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(reply)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBridgeContractsFetchedOnFirstUse(t *testing.T) {
+	l1 := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	l2 := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	var calls atomic.Int32
+	srv := networkStub(t, "0xab5", &BridgeContracts{L1SharedDefaultBridge: &l1, L2SharedDefaultBridge: &l2}, &calls)
+
+	n := AbstractMainnet
+	n.HTTPURL, n.WSURL = srv.URL, ""
+	client, err := DialNetwork(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if got := calls.Load(); got != 0 {
+		t.Fatalf("dialing made %d zks_getBridgeContracts calls, want none", got)
+	}
+
+	for i := 0; i < 2; i++ {
+		bridges, err := client.BridgeContracts(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bridges == nil || bridges.L1SharedDefaultBridge == nil || *bridges.L1SharedDefaultBridge != l1 ||
+			bridges.L2SharedDefaultBridge == nil || *bridges.L2SharedDefaultBridge != l2 {
+			t.Fatalf("bridges = %+v, want the node's shared bridges", bridges)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("%d zks_getBridgeContracts calls, want 1", got)
+	}
+	if client.Network().Bridges != nil || AbstractMainnet.Bridges != nil {
+		t.Fatal("the fetched bridges leaked into the network")
+	}
+}
+
+func TestBridgeContractsPrefersKnownBridges(t *testing.T) {
+	var calls atomic.Int32
+	srv := networkStub(t, "0xab5", nil, &calls)
+	l1 := common.HexToAddress("0x00000000000000000000000000000000000000b1")
+
+	n := AbstractMainnet
+	n.HTTPURL, n.WSURL = srv.URL, ""
+	n.Bridges = &BridgeContracts{L1SharedDefaultBridge: &l1}
+	client, err := DialNetwork(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	got, err := client.BridgeContracts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != n.Bridges || calls.Load() != 0 {
+		t.Fatalf("bridges = %+v after %d calls, want the network's own without asking", got, calls.Load())
+	}
+}
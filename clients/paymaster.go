@@ -0,0 +1,62 @@
+package clients
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PaymasterFlowABI is the IPaymasterFlow interface used to encode paymaster inputs.
+const PaymasterFlowABI = `[
+	{"inputs":[{"name":"input","type":"bytes"}],"name":"general","outputs":[],"stateMutability":"nonpayable","type":"function"},
+	{"inputs":[{"name":"_token","type":"address"},{"name":"_minAllowance","type":"uint256"},{"name":"_innerInput","type":"bytes"}],"name":"approvalBased","outputs":[],"stateMutability":"nonpayable","type":"function"}
+]`
+
+var paymasterFlowABI = mustParseABI(PaymasterFlowABI)
+
+// GeneralPaymasterParams builds paymaster params for the general flow.
+// innerInput is passed through to the paymaster and may be nil.
+func GeneralPaymasterParams(paymaster common.Address, innerInput []byte) (*PaymasterParams, error) {
+	if innerInput == nil {
+		innerInput = []byte{}
+	}
+	input, err := paymasterFlowABI.Pack("general", innerInput)
+	if err != nil {
+		return nil, err
+	}
+	return &PaymasterParams{
+		Paymaster:      paymaster,
+		PaymasterInput: input,
+	}, nil
+}
+
+// ApprovalBasedPaymasterParams builds paymaster params for the approval-based flow.
+// The paymaster is allowed to pull up to minAllowance of token to cover the fee.
+func ApprovalBasedPaymasterParams(paymaster, token common.Address, minAllowance *big.Int, innerInput []byte) (*PaymasterParams, error) {
+	if minAllowance == nil {
+		return nil, errors.New("minAllowance is nil")
+	}
+	if innerInput == nil {
+		innerInput = []byte{}
+	}
+	input, err := paymasterFlowABI.Pack("approvalBased", token, minAllowance, innerInput)
+	if err != nil {
+		return nil, err
+	}
+	return &PaymasterParams{
+		Paymaster:      paymaster,
+		PaymasterInput: input,
+	}, nil
+}
+
+// mustParseABI parses a constant ABI definition and panics on error.
+func mustParseABI(abiJSON string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,65 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestTransferWithPaymasterSendsEIP712(t *testing.T) {
+	fake := NewFakeBackend().On("eth_estimateGas", "0x5208")
+	client, err := NewClientFromBackend(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := NewERC20(client, common.HexToAddress("0x00000000000000000000000000000000000000cc"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paymaster := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	params, err := ApprovalBasedPaymasterParams(paymaster, token.addr, big.NewInt(1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := token.Transfer(context.Background(), w, common.HexToAddress("0x01"), big.NewInt(5),
+		WithPaymaster(params), WithBuildOnly(), WithNonce(0), WithChainID(big.NewInt(2741)),
+		WithGasTipCap(big.NewInt(1)), WithGasFeeCap(big.NewInt(2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx712, ok := tx.(*Transaction712)
+	if !ok {
+		t.Fatalf("tx is %T, want *Transaction712", tx)
+	}
+	if pp := tx712.PaymasterParams(); pp == nil || pp.Paymaster != paymaster {
+		t.Fatalf("paymaster params = %+v, want paymaster %s", pp, paymaster)
+	}
+
+	// Gas is estimated with the paymaster attached
+	calls := fake.CallsTo("eth_estimateGas")
+	if len(calls) != 1 {
+		t.Fatalf("eth_estimateGas called %d times, want 1", len(calls))
+	}
+	var arg struct {
+		EIP712Meta struct {
+			PaymasterParams *struct {
+				Paymaster common.Address `json:"paymaster"`
+			} `json:"paymasterParams"`
+		} `json:"eip712Meta"`
+	}
+	if err := json.Unmarshal(calls[0].Params[0], &arg); err != nil {
+		t.Fatal(err)
+	}
+	if pp := arg.EIP712Meta.PaymasterParams; pp == nil || pp.Paymaster != paymaster {
+		t.Fatalf("gas estimated without the paymaster: %s", calls[0].Params[0])
+	}
+}
@@ -0,0 +1,269 @@
+package clients
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// SetPollInterval changes how often polled subscriptions query the node.
+// Only affects subscriptions created afterwards.
+func (c *Client) SetPollInterval(d time.Duration) {
+	if d > 0 {
+		c.pollInterval.Store(int64(d))
+	}
+}
+
+// pollEvery returns the current poll interval.
+func (c *Client) pollEvery() time.Duration {
+	return time.Duration(c.pollInterval.Load())
+}
+
+// MaxPollFailures is how many polls in a row may fail before a polled subscription
+// ends with the last error. Failed polls are retried at the poll interval.
+const MaxPollFailures = 5
+
+// pollFailed logs a failed poll and reports whether the subscription should keep polling.
+// failures counts the polls that failed in a row, including this one. args are logged
+// after the kind.
+func (c *Client) pollFailed(ctx context.Context, kind string, failures *int, err error, args ...any) bool {
+	*failures++
+	args = append([]any{LogKeyKind, kind}, args...)
+	c.Logger().WarnContext(ctx, "poll failed", append(args, LogKeyAttempt, *failures, LogKeyError, err)...)
+	return *failures < MaxPollFailures
+}
+
+// pollNewHeads emulates a newHeads subscription by polling the latest header.
+// Every block is delivered in order, including blocks mined between two polls.
+func (c *Client) pollNewHeads(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	head, err := c.Eth.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	last := head.Number.Uint64()
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		ticker := time.NewTicker(c.pollEvery())
+		defer ticker.Stop()
+
+		failures := 0
+	poll:
+		for {
+			select {
+			case <-ticker.C:
+			case <-quit:
+				return nil
+			case <-ctx.Done():
+				return nil
+			}
+
+			latest, err := c.Eth.BlockNumber(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				if !c.pollFailed(ctx, "newHeads", &failures, err) {
+					return err
+				}
+				continue
+			}
+			for n := last + 1; n <= latest; n++ {
+				header, err := c.Eth.HeaderByNumber(ctx, new(big.Int).SetUint64(n))
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
+					if !c.pollFailed(ctx, "newHeads", &failures, err) {
+						return err
+					}
+					continue poll
+				}
+				select {
+				case ch <- header:
+				case <-quit:
+					return nil
+				case <-ctx.Done():
+					return nil
+				}
+				last = n
+			}
+			failures = 0
+		}
+	}), nil
+}
+
+// pollHeadNumbers is a cheaper pollNewHeads for callers that only need to know the head
+// moved: it sends the new head number whenever eth_blockNumber changes, without fetching
+// headers. Blocks mined between two polls are reported once.
+func (c *Client) pollHeadNumbers(ctx context.Context, ch chan<- uint64) (ethereum.Subscription, error) {
+	last, err := c.Eth.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		ticker := time.NewTicker(c.pollEvery())
+		defer ticker.Stop()
+
+		failures := 0
+		for {
+			select {
+			case <-ticker.C:
+			case <-quit:
+				return nil
+			case <-ctx.Done():
+				return nil
+			}
+
+			latest, err := c.Eth.BlockNumber(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				if !c.pollFailed(ctx, "newHeads", &failures, err) {
+					return err
+				}
+				continue
+			}
+			failures = 0
+			if latest == last {
+				continue
+			}
+			select {
+			case ch <- latest:
+			case <-quit:
+				return nil
+			case <-ctx.Done():
+				return nil
+			}
+			last = latest
+		}
+	}), nil
+}
+
+// pollLogs emulates a logs subscription by filtering each newly mined block range.
+// Starts at query.FromBlock if set, otherwise at the next block. If resume is not nil,
+// the next block to poll is kept in it, so that a restart can carry on from there.
+func (c *Client) pollLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log, resume *atomic.Uint64) (ethereum.Subscription, error) {
+	latest, err := c.Eth.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	next := latest + 1
+	if query.FromBlock != nil && query.FromBlock.Sign() >= 0 {
+		next = query.FromBlock.Uint64()
+	}
+	if resume == nil {
+		resume = new(atomic.Uint64)
+	}
+	resume.Store(next)
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		ticker := time.NewTicker(c.pollEvery())
+		defer ticker.Stop()
+
+		failures := 0
+		for {
+			latest, err := c.Eth.BlockNumber(ctx)
+			if err == nil {
+				if query.ToBlock != nil && query.ToBlock.Sign() >= 0 && latest > query.ToBlock.Uint64() {
+					latest = query.ToBlock.Uint64()
+				}
+
+				if latest >= next {
+					q := query
+					q.BlockHash = nil
+					q.FromBlock = new(big.Int).SetUint64(next)
+					q.ToBlock = new(big.Int).SetUint64(latest)
+					var logs []types.Log
+					if logs, err = c.Eth.FilterLogs(ctx, q); err == nil {
+						for _, l := range logs {
+							select {
+							case ch <- l:
+							case <-quit:
+								return nil
+							case <-ctx.Done():
+								return nil
+							}
+						}
+						next = latest + 1
+						resume.Store(next)
+					}
+				}
+			}
+			switch {
+			case err == nil:
+				failures = 0
+				if query.ToBlock != nil && query.ToBlock.Sign() >= 0 && next > query.ToBlock.Uint64() {
+					return nil
+				}
+			case ctx.Err() != nil:
+				return nil
+			case !c.pollFailed(ctx, "logs", &failures, err, LogKeyContract, query.Addresses):
+				return err
+			}
+
+			select {
+			case <-ticker.C:
+			case <-quit:
+				return nil
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}), nil
+}
+
+// pollPendingTxs emulates a newPendingTransactions subscription with a node-side filter.
+// The filter is uninstalled when the subscription ends.
+func (c *Client) pollPendingTxs(ctx context.Context, ch chan<- common.Hash) (ethereum.Subscription, error) {
+	var filterID string
+	if err := c.RpcClient.CallContext(ctx, &filterID, "eth_newPendingTransactionFilter"); err != nil {
+		return nil, err
+	}
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer c.RpcClient.Call(nil, "eth_uninstallFilter", filterID)
+
+		ticker := time.NewTicker(c.pollEvery())
+		defer ticker.Stop()
+
+		failures := 0
+		for {
+			select {
+			case <-ticker.C:
+			case <-quit:
+				return nil
+			case <-ctx.Done():
+				return nil
+			}
+
+			var hashes []common.Hash
+			if err := c.RpcClient.CallContext(ctx, &hashes, "eth_getFilterChanges", filterID); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				if !c.pollFailed(ctx, "pendingTxs", &failures, err) {
+					return err
+				}
+				continue
+			}
+			failures = 0
+			for _, h := range hashes {
+				select {
+				case ch <- h:
+				case <-quit:
+					return nil
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+	}), nil
+}
@@ -0,0 +1,174 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestPollLogsSurvivesFailedPolls(t *testing.T) {
+	contract := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	fake := NewFakeBackend().
+		Once("eth_blockNumber", "0x1").
+		OnceError("eth_blockNumber", errors.New("bad gateway")).
+		OnceError("eth_blockNumber", errors.New("bad gateway")).
+		On("eth_blockNumber", "0x2").
+		On("eth_getLogs", []types.Log{{Address: contract, BlockNumber: 2, Topics: []common.Hash{}}})
+	var out syncBuffer
+	client, err := NewClientFromBackend(fake, WithLogger(slog.New(slog.NewTextHandler(&out, nil))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.SetPollInterval(time.Millisecond)
+
+	logs := make(chan types.Log)
+	sub, err := client.SubscribeLogs(context.Background(), ethereum.FilterQuery{Addresses: []common.Address{contract}}, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	select {
+	case l := <-logs:
+		if l.Address != contract || l.BlockNumber != 2 {
+			t.Fatalf("log = %+v, want block 2 of %s", l, contract)
+		}
+	case err := <-sub.Err():
+		t.Fatalf("subscription ended: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no log delivered")
+	}
+	for _, want := range []string{
+		"msg=\"poll failed\" kind=logs contract=[" + contract.Hex() + "] attempt=1",
+		"msg=\"poll failed\" kind=logs contract=[" + contract.Hex() + "] attempt=2",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("logs lack %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestPollNewHeadsGivesUpAfterMaxPollFailures(t *testing.T) {
+	fake := NewFakeBackend().
+		On("eth_getBlockByNumber", &types.Header{Number: common.Big1, Difficulty: common.Big0}).
+		OnError("eth_blockNumber", errors.New("bad gateway"))
+	client, err := NewClientFromBackend(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.SetPollInterval(time.Millisecond)
+
+	sub, err := client.SubscribeNewHeads(context.Background(), make(chan *types.Header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	select {
+	case err := <-sub.Err():
+		if err == nil {
+			t.Fatal("subscription ended without an error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription did not give up")
+	}
+	if n := len(fake.CallsTo("eth_blockNumber")); n != MaxPollFailures {
+		t.Fatalf("polled %d times, want %d", n, MaxPollFailures)
+	}
+}
+
+func TestResubscribedPollLogsResumeAfterLastDeliveredBlock(t *testing.T) {
+	for name, from := range map[string]*big.Int{"latest": nil, "fromBlock": big.NewInt(2)} {
+		t.Run(name, func(t *testing.T) {
+			fake := NewFakeBackend().
+				Once("eth_blockNumber", "0x1").
+				Once("eth_blockNumber", "0x2")
+			for i := 0; i < MaxPollFailures; i++ {
+				fake.OnceError("eth_blockNumber", errors.New("bad gateway"))
+			}
+			// Blocks 3 and 4 are mined during the outage; each block holds one log.
+			fake.On("eth_blockNumber", "0x4").
+				OnFunc("eth_getLogs", func(params []json.RawMessage) (interface{}, error) {
+					var q struct{ FromBlock, ToBlock hexutil.Uint64 }
+					if err := json.Unmarshal(params[0], &q); err != nil {
+						return nil, err
+					}
+					var logs []types.Log
+					for n := q.FromBlock; n <= q.ToBlock; n++ {
+						logs = append(logs, types.Log{BlockNumber: uint64(n), Topics: []common.Hash{}})
+					}
+					return logs, nil
+				})
+			client, err := NewClientFromBackend(fake)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+			client.SetPollInterval(time.Millisecond)
+
+			logs := make(chan types.Log)
+			sub, err := client.resubscribeLogs(context.Background(), ethereum.FilterQuery{FromBlock: from}, logs, "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sub.Unsubscribe()
+
+			for want := uint64(2); want <= 4; want++ {
+				select {
+				case l := <-logs:
+					if l.BlockNumber != want {
+						t.Fatalf("log of block %d, want block %d", l.BlockNumber, want)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("no log of block %d", want)
+				}
+			}
+		})
+	}
+}
+
+func TestPollHeadNumbersOnlyPollsBlockNumber(t *testing.T) {
+	fake := NewFakeBackend().
+		Once("eth_blockNumber", "0x1").
+		Once("eth_blockNumber", "0x1").
+		On("eth_blockNumber", "0x3")
+	client, err := NewClientFromBackend(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.SetPollInterval(time.Millisecond)
+
+	numbers := make(chan uint64)
+	sub, err := client.pollHeadNumbers(context.Background(), numbers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	select {
+	case n := <-numbers:
+		if n != 3 {
+			t.Fatalf("head = %d, want 3", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no head reported")
+	}
+	for _, call := range fake.Calls() {
+		if call.Method != "eth_blockNumber" {
+			t.Fatalf("unexpected %s call", call.Method)
+		}
+	}
+}
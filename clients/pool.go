@@ -0,0 +1,375 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// DefaultMaxBlockLag is how many blocks an endpoint may trail the best one and stay healthy.
+	DefaultMaxBlockLag = 5
+
+	// DefaultHealthCheckInterval is how often the pool re-checks every endpoint.
+	DefaultHealthCheckInterval = 10 * time.Second
+)
+
+// EndpointStatus is a snapshot of one pool endpoint's health.
+type EndpointStatus struct {
+	URL       string
+	Healthy   bool
+	Height    uint64
+	LastError error
+	CheckedAt time.Time
+}
+
+// EndpointPool is an http.RoundTripper that spreads JSON-RPC requests over several endpoints.
+// Requests go to healthy endpoints first and reads fail over on transport errors or 5xx responses.
+type EndpointPool struct {
+	endpoints []*poolEndpoint
+	base      http.RoundTripper
+	maxLag    uint64
+	interval  time.Duration
+	timeout   time.Duration
+
+	dialOpts []DialOption
+
+	next      atomic.Uint32
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+type poolEndpoint struct {
+	url *url.URL
+
+	mu        sync.RWMutex
+	healthy   bool
+	height    uint64
+	lastErr   error
+	checkedAt time.Time
+}
+
+// PoolOption customizes an EndpointPool.
+type PoolOption func(*EndpointPool)
+
+// WithMaxBlockLag sets how far behind the best block an endpoint may be and stay healthy.
+func WithMaxBlockLag(blocks uint64) PoolOption {
+	return func(p *EndpointPool) {
+		p.maxLag = blocks
+	}
+}
+
+// WithHealthCheckInterval sets how often endpoints are re-checked.
+func WithHealthCheckInterval(d time.Duration) PoolOption {
+	return func(p *EndpointPool) {
+		if d > 0 {
+			p.interval = d
+		}
+	}
+}
+
+// WithPoolTransport sets the transport used to reach the endpoints.
+func WithPoolTransport(rt http.RoundTripper) PoolOption {
+	return func(p *EndpointPool) {
+		if rt != nil {
+			p.base = rt
+		}
+	}
+}
+
+// WithDialOptions applies client dial options, such as WithRetryPolicy, to a pooled client.
+// Used by DialPool; ignored by a bare EndpointPool.
+func WithDialOptions(opts ...DialOption) PoolOption {
+	return func(p *EndpointPool) {
+		p.dialOpts = append(p.dialOpts, opts...)
+	}
+}
+
+// NewEndpointPool creates a pool over the given HTTP endpoints, all initially healthy.
+// Call Start to run periodic health checks and Close to stop them.
+func NewEndpointPool(urls []string, opts ...PoolOption) (*EndpointPool, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("endpoint pool requires at least one URL")
+	}
+
+	p := &EndpointPool{
+		base:     http.DefaultTransport,
+		maxLag:   DefaultMaxBlockLag,
+		interval: DefaultHealthCheckInterval,
+		timeout:  5 * time.Second,
+		stop:     make(chan struct{}),
+	}
+	for _, raw := range urls {
+		if !strings.HasPrefix(raw, "http") {
+			return nil, fmt.Errorf("endpoint pool requires http:// or https:// URLs, got %q", raw)
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		p.endpoints = append(p.endpoints, &poolEndpoint{url: u, healthy: true})
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// DialPool creates a client whose calls are routed through a pool of HTTP endpoints.
+// Endpoints are health-checked once before returning and periodically afterwards.
+func DialPool(urls []string, opts ...PoolOption) (*Client, error) {
+	pool, err := NewEndpointPool(urls, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cfg := newDialConfig(pool.dialOpts)
+	if pool.base == http.DefaultTransport {
+		pool.base = cfg.baseTransport()
+	}
+	pool.base = cfg.endpointTransport(pool.base)
+
+	ctx, cancel := context.WithTimeout(context.Background(), pool.timeout)
+	pool.CheckHealth(ctx)
+	cancel()
+	pool.Start()
+
+	transport := cfg.transport(pool)
+	rpcClient, err := rpc.DialOptions(context.Background(), urls[0], rpc.WithHTTPClient(cfg.newHTTPClient(transport)))
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	c := newClient(rpcClient, nil)
+	c.pool = pool
+	c.onClose = append(c.onClose, pool.Close)
+	return cfg.attach(c)
+}
+
+// Pool returns the endpoint pool behind the client, or nil if it was not dialed with DialPool.
+func (c *Client) Pool() *EndpointPool {
+	return c.pool
+}
+
+// Start runs health checks in the background until Close is called.
+func (p *EndpointPool) Start() {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+				p.CheckHealth(ctx)
+				cancel()
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the background health checks.
+func (p *EndpointPool) Close() {
+	p.closeOnce.Do(func() { close(p.stop) })
+}
+
+// CheckHealth queries the block height of every endpoint and updates their health.
+// An endpoint is unhealthy if it errors or lags more than the max block lag behind the best.
+func (p *EndpointPool) CheckHealth(ctx context.Context) {
+	heights := make([]uint64, len(p.endpoints))
+	errs := make([]error, len(p.endpoints))
+
+	var wg sync.WaitGroup
+	for i, ep := range p.endpoints {
+		wg.Add(1)
+		go func(i int, ep *poolEndpoint) {
+			defer wg.Done()
+			heights[i], errs[i] = p.blockNumber(ctx, ep)
+		}(i, ep)
+	}
+	wg.Wait()
+
+	var best uint64
+	for i := range p.endpoints {
+		if errs[i] == nil && heights[i] > best {
+			best = heights[i]
+		}
+	}
+
+	now := time.Now()
+	for i, ep := range p.endpoints {
+		ep.mu.Lock()
+		ep.checkedAt = now
+		ep.lastErr = errs[i]
+		if errs[i] == nil {
+			ep.height = heights[i]
+			if best-heights[i] > p.maxLag {
+				ep.lastErr = fmt.Errorf("lagging %d blocks behind best height %d", best-heights[i], best)
+			}
+		}
+		ep.healthy = ep.lastErr == nil
+		ep.mu.Unlock()
+	}
+}
+
+// Status returns a snapshot of every endpoint in configuration order.
+func (p *EndpointPool) Status() []EndpointStatus {
+	out := make([]EndpointStatus, len(p.endpoints))
+	for i, ep := range p.endpoints {
+		ep.mu.RLock()
+		out[i] = EndpointStatus{
+			URL:       ep.url.String(),
+			Healthy:   ep.healthy,
+			Height:    ep.height,
+			LastError: ep.lastErr,
+			CheckedAt: ep.checkedAt,
+		}
+		ep.mu.RUnlock()
+	}
+	return out
+}
+
+// RoundTrip sends the request to a healthy endpoint, failing over to the next one
+// on transport errors or 5xx responses. Unhealthy endpoints are tried last.
+// Requests that change node state, such as transaction sends, only fail over when
+// the connection could not be opened, so no endpoint ever receives them twice.
+func (p *EndpointPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	idempotent := classifyRequest(body, IsIdempotentMethod) == retryRead
+
+	var lastErr error
+	for _, ep := range p.candidates() {
+		resp, err := p.base.RoundTrip(withEndpoint(req, ep.url, body))
+		if err != nil {
+			if req.Context().Err() != nil {
+				return nil, err
+			}
+			ep.markDown(err)
+			if !idempotent && !isDialError(err) {
+				// The endpoint may have received the request
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			lastErr = fmt.Errorf("%s: %s", ep.url.Host, resp.Status)
+			ep.markDown(lastErr)
+			if !idempotent {
+				return resp, nil
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			continue
+		}
+		return resp, nil
+	}
+	return nil, lastErr
+}
+
+// candidates returns the endpoints in try order: healthy ones round-robin, then the rest.
+func (p *EndpointPool) candidates() []*poolEndpoint {
+	start := int(p.next.Add(1)-1) % len(p.endpoints)
+	healthy := make([]*poolEndpoint, 0, len(p.endpoints))
+	var unhealthy []*poolEndpoint
+	for i := range p.endpoints {
+		ep := p.endpoints[(start+i)%len(p.endpoints)]
+		ep.mu.RLock()
+		ok := ep.healthy
+		ep.mu.RUnlock()
+		if ok {
+			healthy = append(healthy, ep)
+		} else {
+			unhealthy = append(unhealthy, ep)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// blockNumber calls eth_blockNumber directly on one endpoint.
+func (p *EndpointPool) blockNumber(ctx context.Context, ep *poolEndpoint) (uint64, error) {
+	payload := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.url.String(), bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.base.RoundTrip(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("%s: %s", ep.url.Host, resp.Status)
+	}
+
+	var out struct {
+		Result *hexutil.Uint64 `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return 0, err
+	}
+	if out.Error != nil {
+		return 0, errors.New(out.Error.Message)
+	}
+	if out.Result == nil {
+		return 0, errors.New("empty eth_blockNumber result")
+	}
+	return uint64(*out.Result), nil
+}
+
+// markDown flags the endpoint unhealthy until the next successful health check.
+func (ep *poolEndpoint) markDown(err error) {
+	ep.mu.Lock()
+	ep.healthy = false
+	ep.lastErr = err
+	ep.mu.Unlock()
+}
+
+// readRequestBody reads and restores the request body so it can be replayed.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// withEndpoint clones the request, pointing it at target with a fresh copy of body.
+func withEndpoint(req *http.Request, target *url.URL, body []byte) *http.Request {
+	r := req.Clone(req.Context())
+	u := *target
+	r.URL = &u
+	r.Host = u.Host
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return r
+}
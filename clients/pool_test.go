@@ -0,0 +1,250 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rpcStub is a JSON-RPC stand-in for a node, serving eth_blockNumber from height and
+// answering every other method with "0x1". status, when set, replaces every reply.
+type rpcStub struct {
+	*httptest.Server
+
+	height atomic.Uint64
+	status atomic.Int32
+	drop   atomic.Bool // close the connection without replying
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func newRPCStub(t *testing.T, height uint64) *rpcStub {
+	t.Helper()
+	s := &rpcStub{}
+	s.height.Store(height)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *rpcStub) serve(w http.ResponseWriter, r *http.Request) {
+	var req rpcMessage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.calls = append(s.calls, req.Method)
+	s.mu.Unlock()
+
+	if s.drop.Load() {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+		return
+	}
+	if status := int(s.status.Load()); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	result, _ := json.Marshal("0x1")
+	if req.Method == "eth_blockNumber" {
+		result, _ = json.Marshal(hexutil.Uint64(s.height.Load()))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rpcMessage{Version: "2.0", ID: req.ID, Result: result})
+}
+
+// callsTo returns how many times method reached the stub.
+func (s *rpcStub) callsTo(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, m := range s.calls {
+		if m == method {
+			n++
+		}
+	}
+	return n
+}
+
+// closedURL returns the URL of a server that no longer accepts connections.
+func closedURL(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	return "http://" + addr
+}
+
+// dialPoolRPC returns an rpc client that sends through pool.
+func dialPoolRPC(t *testing.T, pool *EndpointPool) *rpc.Client {
+	t.Helper()
+	c, err := rpc.DialOptions(context.Background(), "http://pool.invalid", rpc.WithHTTPClient(&http.Client{Transport: pool}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+func newTestPool(t *testing.T, urls []string, opts ...PoolOption) *EndpointPool {
+	t.Helper()
+	pool, err := NewEndpointPool(urls, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestPoolFailsOverOnDialError(t *testing.T) {
+	good := newRPCStub(t, 10)
+	pool := newTestPool(t, []string{closedURL(t), good.URL})
+	c := dialPoolRPC(t, pool)
+
+	var balance hexutil.Big
+	if err := c.Call(&balance, "eth_getBalance", "0x0000000000000000000000000000000000000001", "latest"); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	// The send never reached the first endpoint, so it may go to the next one
+	var hash string
+	if err := c.Call(&hash, "eth_sendRawTransaction", "0x00"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if got := good.callsTo("eth_sendRawTransaction"); got != 1 {
+		t.Fatalf("send reached the healthy endpoint %d times, want 1", got)
+	}
+	if status := pool.Status(); status[0].Healthy || !status[1].Healthy {
+		t.Fatalf("status after dial error = %+v, want only the first endpoint down", status)
+	}
+}
+
+func TestPoolFailsOverReadsOnTransportError(t *testing.T) {
+	bad, good := newRPCStub(t, 10), newRPCStub(t, 10)
+	bad.drop.Store(true)
+	pool := newTestPool(t, []string{bad.URL, good.URL})
+	c := dialPoolRPC(t, pool)
+
+	var balance hexutil.Big
+	if err := c.Call(&balance, "eth_getBalance", "0x0000000000000000000000000000000000000001", "latest"); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if good.callsTo("eth_getBalance") != 1 {
+		t.Fatal("read did not fail over to the healthy endpoint")
+	}
+	if pool.Status()[0].Healthy {
+		t.Fatal("endpoint that dropped the connection is still healthy")
+	}
+}
+
+func TestPoolDoesNotFailOverSendAfterTransportError(t *testing.T) {
+	bad, good := newRPCStub(t, 10), newRPCStub(t, 10)
+	bad.drop.Store(true)
+	pool := newTestPool(t, []string{bad.URL, good.URL})
+	c := dialPoolRPC(t, pool)
+
+	var hash string
+	if err := c.Call(&hash, "eth_sendRawTransaction", "0x00"); err == nil {
+		t.Fatal("send succeeded, want the transport error")
+	}
+	if got := good.callsTo("eth_sendRawTransaction"); got != 0 {
+		t.Fatalf("send reached a second endpoint %d times", got)
+	}
+}
+
+func TestPoolFailsOverOn5xx(t *testing.T) {
+	bad, good := newRPCStub(t, 10), newRPCStub(t, 10)
+	bad.status.Store(http.StatusServiceUnavailable)
+	pool := newTestPool(t, []string{bad.URL, good.URL})
+	c := dialPoolRPC(t, pool)
+
+	var n hexutil.Uint64
+	if err := c.Call(&n, "eth_blockNumber"); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if uint64(n) != 10 {
+		t.Fatalf("block number = %d, want 10", n)
+	}
+	if pool.Status()[0].Healthy {
+		t.Fatal("endpoint answering 503 is still healthy")
+	}
+
+	// Unhealthy endpoints are tried last, so put the failing one back in front
+	bad.status.Store(0)
+	pool.CheckHealth(context.Background())
+	bad.status.Store(http.StatusServiceUnavailable)
+	pool.next.Store(0)
+	var hash string
+	err := c.Call(&hash, "eth_sendRawTransaction", "0x00")
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("send error = %v, want the 503 response", err)
+	}
+	if got := good.callsTo("eth_sendRawTransaction"); got != 0 {
+		t.Fatalf("send reached a second endpoint %d times", got)
+	}
+}
+
+func TestPoolMarksLaggingEndpointUnhealthy(t *testing.T) {
+	leader, lagging := newRPCStub(t, 100), newRPCStub(t, 90)
+	pool := newTestPool(t, []string{leader.URL, lagging.URL}, WithMaxBlockLag(5))
+
+	pool.CheckHealth(context.Background())
+	status := pool.Status()
+	if !status[0].Healthy || status[1].Healthy {
+		t.Fatalf("status = %+v, want the lagging endpoint unhealthy", status)
+	}
+	if status[1].Height != 90 || status[1].LastError == nil {
+		t.Fatalf("lagging status = %+v, want height 90 and an error", status[1])
+	}
+
+	// Requests prefer the healthy endpoint even when it is not next in turn
+	pool.next.Store(1)
+	c := dialPoolRPC(t, pool)
+	var balance hexutil.Big
+	if err := c.Call(&balance, "eth_getBalance", "0x0000000000000000000000000000000000000001", "latest"); err != nil {
+		t.Fatal(err)
+	}
+	if lagging.callsTo("eth_getBalance") != 0 {
+		t.Fatal("read was sent to the lagging endpoint")
+	}
+
+	// Within the lag the endpoint is healthy again
+	lagging.height.Store(96)
+	pool.CheckHealth(context.Background())
+	if !pool.Status()[1].Healthy {
+		t.Fatalf("status = %+v, want the caught-up endpoint healthy", pool.Status()[1])
+	}
+}
+
+func TestPoolRecoversOnNextCheckHealth(t *testing.T) {
+	flaky, good := newRPCStub(t, 10), newRPCStub(t, 10)
+	flaky.status.Store(http.StatusBadGateway)
+	pool := newTestPool(t, []string{flaky.URL, good.URL})
+
+	pool.CheckHealth(context.Background())
+	if pool.Status()[0].Healthy {
+		t.Fatal("endpoint answering 502 is healthy")
+	}
+
+	flaky.status.Store(0)
+	pool.CheckHealth(context.Background())
+	status := pool.Status()[0]
+	if !status.Healthy || status.LastError != nil {
+		t.Fatalf("status = %+v, want the endpoint recovered", status)
+	}
+}
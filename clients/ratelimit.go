@@ -0,0 +1,248 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimit is a token bucket: Rate requests per second with bursts of up to Burst.
+// A zero Rate means unlimited.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitConfig configures client-side request budgeting.
+// Each call consumes a token from its endpoint bucket and from its method bucket, if any.
+type RateLimitConfig struct {
+	PerEndpoint RateLimit            // applied to each endpoint host separately
+	PerMethod   map[string]RateLimit // applied to a method across all endpoints
+
+	// Priority reports whether a method uses the priority lane. Pending priority calls
+	// are served before any queued normal call. Defaults to raw transaction sends.
+	Priority func(method string) bool
+}
+
+// QueueDepth reports how many calls are waiting for a rate limit token.
+type QueueDepth struct {
+	Normal   int
+	Priority int
+}
+
+// WithRateLimit throttles RPC calls on the client side with token buckets.
+// Applies to calls on every transport, but not to subscription notifications; the limiter
+// is shared by all goroutines using the client.
+func WithRateLimit(cfg RateLimitConfig) DialOption {
+	return func(dc *dialConfig) {
+		dc.rateLimit = &cfg
+	}
+}
+
+// QueueDepth returns the number of calls currently waiting on the rate limiter.
+// Always zero when the client was dialed without WithRateLimit.
+func (c *Client) QueueDepth() QueueDepth {
+	if c.limiter == nil {
+		return QueueDepth{}
+	}
+	return c.limiter.depth()
+}
+
+type tokenBucket struct {
+	rate            float64
+	burst           float64
+	tokens          float64
+	last            time.Time
+	priorityWaiting int
+}
+
+// refill adds the tokens accumulated since the last update.
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// delay returns how long until n tokens can be taken; n is capped at the burst size.
+func (b *tokenBucket) delay(n float64) time.Duration {
+	if n > b.burst {
+		n = b.burst
+	}
+	if b.tokens >= n {
+		return 0
+	}
+	return time.Duration((n - b.tokens) / b.rate * float64(time.Second))
+}
+
+type rateLimiter struct {
+	cfg RateLimitConfig
+
+	mu        sync.Mutex
+	endpoints map[string]*tokenBucket
+	methods   map[string]*tokenBucket
+	waiting   QueueDepth
+}
+
+func newRateLimiter(cfg RateLimitConfig) *rateLimiter {
+	if cfg.Priority == nil {
+		cfg.Priority = isRawTxSend
+	}
+	return &rateLimiter{
+		cfg:       cfg,
+		endpoints: make(map[string]*tokenBucket),
+		methods:   make(map[string]*tokenBucket),
+	}
+}
+
+// transport returns a RoundTripper that waits for tokens before calling next.
+func (l *rateLimiter) transport(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := readRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+		if err := l.wait(req.Context(), req.URL.Host, rpcMethods(body)); err != nil {
+			return nil, err
+		}
+		return next.RoundTrip(req)
+	})
+}
+
+// wait blocks until every bucket involved in the call has enough tokens, then takes them.
+func (l *rateLimiter) wait(ctx context.Context, host string, methods []string) error {
+	priority := false
+	for _, m := range methods {
+		if l.cfg.Priority(m) {
+			priority = true
+		}
+	}
+	n := float64(len(methods))
+	if n == 0 {
+		n = 1
+	}
+
+	var queued []*tokenBucket // the buckets waited on, once queued
+	defer func() {
+		if queued != nil {
+			l.mu.Lock()
+			l.leaveQueue(queued, priority)
+			l.mu.Unlock()
+		}
+	}()
+
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		buckets := l.buckets(host, methods, now)
+
+		var delay time.Duration
+		yield := false
+		for _, b := range buckets {
+			if d := b.delay(n); d > delay {
+				delay = d
+			}
+			if !priority && b.priorityWaiting > 0 {
+				yield = true
+			}
+		}
+		if delay == 0 && !yield {
+			for _, b := range buckets {
+				b.tokens -= n
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		if queued == nil {
+			queued = buckets
+			l.joinQueue(queued, priority)
+		}
+		if yield && delay == 0 {
+			// Let the priority call take the token first
+			delay = time.Millisecond
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// buckets returns the refilled buckets that apply to a call; must hold l.mu.
+func (l *rateLimiter) buckets(host string, methods []string, now time.Time) []*tokenBucket {
+	var out []*tokenBucket
+	if b := l.bucket(l.endpoints, host, l.cfg.PerEndpoint, now); b != nil {
+		out = append(out, b)
+	}
+	for _, m := range methods {
+		if limit, ok := l.cfg.PerMethod[m]; ok {
+			if b := l.bucket(l.methods, m, limit, now); b != nil {
+				out = append(out, b)
+			}
+		}
+	}
+	return out
+}
+
+// bucket returns the bucket for key, creating it full; nil if the limit is unlimited.
+func (l *rateLimiter) bucket(set map[string]*tokenBucket, key string, limit RateLimit, now time.Time) *tokenBucket {
+	if limit.Rate <= 0 {
+		return nil
+	}
+	b, ok := set[key]
+	if !ok {
+		burst := float64(limit.Burst)
+		if burst < 1 {
+			burst = 1
+		}
+		b = &tokenBucket{rate: limit.Rate, burst: burst, tokens: burst, last: now}
+		set[key] = b
+	}
+	b.refill(now)
+	return b
+}
+
+// joinQueue records a waiting call. A priority call marks every bucket it waits on,
+// so that normal calls sharing any of them let it go first; must hold l.mu.
+func (l *rateLimiter) joinQueue(buckets []*tokenBucket, priority bool) {
+	if !priority {
+		l.waiting.Normal++
+		return
+	}
+	l.waiting.Priority++
+	for _, b := range buckets {
+		b.priorityWaiting++
+	}
+}
+
+// leaveQueue removes a waiting call queued on buckets; must hold l.mu.
+func (l *rateLimiter) leaveQueue(buckets []*tokenBucket, priority bool) {
+	if !priority {
+		l.waiting.Normal--
+		return
+	}
+	l.waiting.Priority--
+	for _, b := range buckets {
+		b.priorityWaiting--
+	}
+}
+
+func (l *rateLimiter) depth() QueueDepth {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.waiting
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
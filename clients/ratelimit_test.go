@@ -0,0 +1,48 @@
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPriorityCallsGoFirstOnMethodLimits(t *testing.T) {
+	// Only a method limit applies; the priority batch shares its bucket with the normal call
+	l := newRateLimiter(RateLimitConfig{PerMethod: map[string]RateLimit{
+		"eth_getTransactionCount": {Rate: 20, Burst: 1},
+	}})
+	ctx := context.Background()
+	if err := l.wait(ctx, "node", []string{"eth_getTransactionCount"}); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan string, 2)
+	waitFor := func(want QueueDepth) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for l.depth() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("queue depth = %+v, want %+v", l.depth(), want)
+			}
+			time.Sleep(100 * time.Microsecond)
+		}
+	}
+	go func() {
+		l.wait(ctx, "node", []string{"eth_getTransactionCount"})
+		done <- "normal"
+	}()
+	waitFor(QueueDepth{Normal: 1})
+	go func() {
+		l.wait(ctx, "node", []string{"eth_getTransactionCount", "eth_sendRawTransaction"})
+		done <- "priority"
+	}()
+	waitFor(QueueDepth{Normal: 1, Priority: 1})
+
+	if first := <-done; first != "priority" {
+		t.Fatalf("the %s call went first, want the priority call", first)
+	}
+	<-done
+	if got := l.depth(); got != (QueueDepth{}) {
+		t.Fatalf("queue depth = %+v after both calls, want none", got)
+	}
+}
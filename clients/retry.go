@@ -0,0 +1,326 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RetryPolicy controls how failed RPC calls are retried with exponential backoff.
+// Zero MaxAttempts, InitialBackoff, MaxBackoff, Multiplier and Idempotent take the
+// values of DefaultRetryPolicy; zero Jitter and MaxElapsed mean none and no limit.
+//
+// Reads are retried on transport errors, 429 and 5xx responses. Transaction sends
+// and other non-idempotent calls are only retried when the request provably never
+// reached the node (dial errors, 429). If a resent transaction is answered with
+// "already known" or "nonce too low" and the node has it, the send is reported as success.
+type RetryPolicy struct {
+	MaxAttempts    int           // total attempts, including the first
+	InitialBackoff time.Duration // delay before the first retry
+	MaxBackoff     time.Duration // upper bound of a single delay
+	Multiplier     float64       // backoff growth factor per attempt
+	Jitter         float64       // random spread of each delay, as a fraction (0-1)
+	MaxElapsed     time.Duration // give up once this much time has passed; 0 means no limit
+
+	// Idempotent reports whether a method can safely be sent more than once.
+	// Defaults to IsIdempotentMethod.
+	Idempotent func(method string) bool
+}
+
+// DefaultRetryPolicy returns a policy suitable for public RPC endpoints.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		MaxElapsed:     30 * time.Second,
+		Idempotent:     IsIdempotentMethod,
+	}
+}
+
+// nonIdempotentMethods change node state, so a duplicate request is not harmless.
+var nonIdempotentMethods = map[string]bool{
+	"eth_sendRawTransaction":                   true,
+	"eth_sendTransaction":                      true,
+	"zks_sendRawTransactionWithDetailedOutput": true,
+	"eth_newFilter":                            true,
+	"eth_newBlockFilter":                       true,
+	"eth_newPendingTransactionFilter":          true,
+	"eth_uninstallFilter":                      true,
+	"eth_getFilterChanges":                     true,
+}
+
+// IsIdempotentMethod reports whether a JSON-RPC method is a pure read.
+func IsIdempotentMethod(method string) bool {
+	return !nonIdempotentMethods[method]
+}
+
+type retryClass int
+
+const (
+	retryRead   retryClass = iota // safe to repeat
+	retrySend                     // raw tx send: safe if duplicates are recognized
+	retryUnsafe                   // only retry if the node never saw the request
+)
+
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+	logger *loggerRef
+}
+
+// newRetryTransport wraps next with the given policy, filling in defaults.
+// Retries are logged to logger.
+func newRetryTransport(next http.RoundTripper, policy RetryPolicy, logger *loggerRef) *retryTransport {
+	def := DefaultRetryPolicy()
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = def.MaxAttempts
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = def.InitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = def.MaxBackoff
+	}
+	if policy.Multiplier < 1 {
+		policy.Multiplier = def.Multiplier
+	}
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		policy.Jitter = def.Jitter
+	}
+	if policy.Idempotent == nil {
+		policy.Idempotent = def.Idempotent
+	}
+	return &retryTransport{next: next, policy: policy, logger: logger}
+}
+
+// RoundTrip sends the request, retrying with backoff as allowed by the policy.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	class := t.classify(body)
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.next.RoundTrip(withEndpoint(req, req.URL, body))
+		if err != nil && req.Context().Err() != nil {
+			return nil, err
+		}
+
+		retry := false
+		switch {
+		case err != nil:
+			retry = class == retryRead || isDialError(err)
+		case resp.StatusCode == http.StatusTooManyRequests:
+			retry = true
+		case resp.StatusCode >= http.StatusInternalServerError:
+			retry = class == retryRead
+		}
+
+		if !retry && err == nil && class == retrySend && attempt > 1 {
+			// The transaction may have reached the node some other way, e.g. through a
+			// second endpoint or another process sharing the key
+			resp = t.resolveSentTx(req, resp, body)
+		}
+		if !retry || attempt >= t.policy.MaxAttempts {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt, resp)
+		if t.policy.MaxElapsed > 0 && time.Since(start)+delay > t.policy.MaxElapsed {
+			return resp, err
+		}
+		reason := err
+		if resp != nil {
+			reason = errors.New(resp.Status)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		t.logger.get().LogAttrs(req.Context(), slog.LevelWarn, "retrying RPC call",
+			slog.Any("methods", rpcMethods(body)),
+			slog.Int(LogKeyAttempt, attempt),
+			slog.Any(LogKeyError, reason),
+			slog.Duration("delay", delay))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// classify determines how safely the request payload may be repeated.
+func (t *retryTransport) classify(body []byte) retryClass {
+	return classifyRequest(body, t.policy.Idempotent)
+}
+
+// classifyRequest determines how safely a request payload may be sent more than once,
+// given which methods are idempotent.
+func classifyRequest(body []byte, idempotent func(method string) bool) retryClass {
+	methods := rpcMethods(body)
+	if len(methods) == 0 {
+		return retryUnsafe
+	}
+	if len(methods) == 1 && isRawTxSend(methods[0]) {
+		return retrySend
+	}
+	for _, m := range methods {
+		if !idempotent(m) {
+			return retryUnsafe
+		}
+	}
+	return retryRead
+}
+
+// backoff returns the delay before the next attempt, honoring Retry-After on 429.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	delay := float64(t.policy.InitialBackoff) * math.Pow(t.policy.Multiplier, float64(attempt-1))
+	if delay > float64(t.policy.MaxBackoff) {
+		delay = float64(t.policy.MaxBackoff)
+	}
+	if t.policy.Jitter > 0 {
+		delay *= 1 + t.policy.Jitter*(2*rand.Float64()-1)
+	}
+
+	d := time.Duration(delay)
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			if after := time.Duration(secs) * time.Second; after > d {
+				d = after
+			}
+		}
+	}
+	return d
+}
+
+// isRawTxSend reports whether method submits a signed transaction and returns its hash.
+func isRawTxSend(method string) bool {
+	return method == "eth_sendRawTransaction"
+}
+
+// isDialError reports whether err happened before the request was sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// isKnownTxError reports whether a node error means the transaction is already in its pool.
+func isKnownTxError(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "already known") ||
+		strings.Contains(msg, "known transaction") ||
+		strings.Contains(msg, "already exists") ||
+		strings.Contains(msg, "already imported")
+}
+
+// isNonceTooLowError reports whether a node error means the sender's nonce was already used.
+func isNonceTooLowError(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "nonce has already been used")
+}
+
+// resolveSentTx turns the reply to a resent transaction into a successful result
+// carrying its hash when the node already has it: either it answers "already known",
+// or it answers "nonce too low" and returns the transaction when looked up by hash.
+func (t *retryTransport) resolveSentTx(req *http.Request, resp *http.Response, reqBody []byte) *http.Response {
+	if resp.StatusCode != http.StatusOK {
+		return resp
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		return resp
+	}
+
+	msgs, batch, err := parseRPCMessages(respBody)
+	if err != nil || batch || msgs[0].Error == nil {
+		return resp
+	}
+	known, nonceUsed := isKnownTxError(msgs[0].Error.Message), isNonceTooLowError(msgs[0].Error.Message)
+	if !known && !nonceUsed {
+		return resp
+	}
+	reqs, _, err := parseRPCMessages(reqBody)
+	if err != nil {
+		return resp
+	}
+	var params []hexutil.Bytes
+	if err := json.Unmarshal(reqs[0].Params, &params); err != nil || len(params) == 0 {
+		return resp
+	}
+	hash, err := rawTxHash(params[0])
+	if err != nil {
+		return resp
+	}
+	if !known && !t.hasTx(req, hash) {
+		return resp
+	}
+
+	result, _ := json.Marshal(hash)
+	out, _ := json.Marshal(rpcMessage{Version: "2.0", ID: msgs[0].ID, Result: result})
+	resp.Body = io.NopCloser(bytes.NewReader(out))
+	resp.ContentLength = int64(len(out))
+	resp.Header.Del("Content-Length")
+	return resp
+}
+
+// hasTx reports whether the node behind the transport knows the transaction with the given hash.
+func (t *retryTransport) hasTx(req *http.Request, hash common.Hash) bool {
+	params, _ := json.Marshal([]common.Hash{hash})
+	lookup, _ := json.Marshal(rpcMessage{Version: "2.0", ID: json.RawMessage("1"), Method: "eth_getTransactionByHash", Params: params})
+	resp, err := t.next.RoundTrip(withEndpoint(req, req.URL, lookup))
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	var out rpcMessage
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil || out.Error != nil {
+		return false
+	}
+	return len(out.Result) > 0 && string(out.Result) != "null"
+}
+
+// rawTxHash returns the hash of a signed raw transaction, including EIP-712 ones.
+func rawTxHash(raw []byte) (common.Hash, error) {
+	if len(raw) > 0 && raw[0] == EIP712TxType {
+		var tx Transaction712
+		if err := tx.UnmarshalBinary(raw); err != nil {
+			return common.Hash{}, err
+		}
+		return tx.Hash(), nil
+	}
+	var tx types.Transaction
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
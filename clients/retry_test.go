@@ -0,0 +1,172 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// scriptedServer answers each JSON-RPC request with the next reply scripted for its method:
+// an HTTP status code, or a JSON-RPC result or error message.
+type scriptedServer struct {
+	mu      sync.Mutex
+	replies map[string][]scriptedReply
+	calls   map[string]int
+}
+
+type scriptedReply struct {
+	status int
+	result interface{}
+	err    string
+}
+
+func newScriptedServer(t *testing.T, replies map[string][]scriptedReply) (*scriptedServer, string) {
+	t.Helper()
+	s := &scriptedServer{replies: replies, calls: make(map[string]int)}
+	srv := httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(srv.Close)
+	return s, srv.URL
+}
+
+func (s *scriptedServer) serve(w http.ResponseWriter, r *http.Request) {
+	var req rpcMessage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	n := s.calls[req.Method]
+	s.calls[req.Method]++
+	script := s.replies[req.Method]
+	s.mu.Unlock()
+
+	if len(script) == 0 {
+		http.Error(w, "unscripted "+req.Method, http.StatusBadRequest)
+		return
+	}
+	if n >= len(script) {
+		n = len(script) - 1
+	}
+	reply := script[n]
+	if reply.status != 0 {
+		w.WriteHeader(reply.status)
+		return
+	}
+	msg := rpcMessage{Version: "2.0", ID: req.ID}
+	if reply.err != "" {
+		msg.Error = &rpcError{Code: -32000, Message: reply.err}
+	} else {
+		msg.Result, _ = json.Marshal(reply.result)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(msg)
+}
+
+func (s *scriptedServer) callsTo(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+// dialRetrying returns an rpc client that sends to url through a retry transport.
+func dialRetrying(t *testing.T, url string) *rpc.Client {
+	t.Helper()
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+	transport := newRetryTransport(http.DefaultTransport, policy, nil)
+	c, err := rpc.DialOptions(context.Background(), url, rpc.WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+// signedRawTx returns a signed legacy transaction and its encoding.
+func signedRawTx(t *testing.T) (*types.Transaction, hexutil.Bytes) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	tx, err := types.SignNewTx(key, types.NewEIP155Signer(big.NewInt(1)), &types.LegacyTx{
+		Nonce: 7, To: &to, Gas: 21000, GasPrice: big.NewInt(1), Value: big.NewInt(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx, raw
+}
+
+func TestRetryRetriesReadsOn5xx(t *testing.T) {
+	srv, url := newScriptedServer(t, map[string][]scriptedReply{
+		"eth_blockNumber": {{status: http.StatusBadGateway}, {result: "0x2a"}},
+	})
+	var n hexutil.Uint64
+	if err := dialRetrying(t, url).Call(&n, "eth_blockNumber"); err != nil {
+		t.Fatal(err)
+	}
+	if n != 42 || srv.callsTo("eth_blockNumber") != 2 {
+		t.Fatalf("got %d after %d calls, want 42 after 2", n, srv.callsTo("eth_blockNumber"))
+	}
+}
+
+func TestRetryDoesNotResendOn5xx(t *testing.T) {
+	_, raw := signedRawTx(t)
+	srv, url := newScriptedServer(t, map[string][]scriptedReply{
+		"eth_sendRawTransaction": {{status: http.StatusBadGateway}, {result: "0x00"}},
+	})
+	var hash common.Hash
+	if err := dialRetrying(t, url).Call(&hash, "eth_sendRawTransaction", raw); err == nil {
+		t.Fatal("send succeeded, want the 502 error")
+	}
+	if got := srv.callsTo("eth_sendRawTransaction"); got != 1 {
+		t.Fatalf("send was attempted %d times, want 1", got)
+	}
+}
+
+func TestRetryResolvesNonceTooLowForKnownTx(t *testing.T) {
+	tx, raw := signedRawTx(t)
+	srv, url := newScriptedServer(t, map[string][]scriptedReply{
+		"eth_sendRawTransaction":   {{status: http.StatusTooManyRequests}, {err: "nonce too low"}},
+		"eth_getTransactionByHash": {{result: map[string]string{"hash": tx.Hash().Hex()}}},
+	})
+	var hash common.Hash
+	if err := dialRetrying(t, url).Call(&hash, "eth_sendRawTransaction", raw); err != nil {
+		t.Fatal(err)
+	}
+	if hash != tx.Hash() {
+		t.Fatalf("hash = %s, want %s", hash, tx.Hash())
+	}
+	if srv.callsTo("eth_getTransactionByHash") != 1 {
+		t.Fatal("transaction was not looked up by hash")
+	}
+}
+
+func TestRetryKeepsNonceTooLowForUnknownTx(t *testing.T) {
+	_, raw := signedRawTx(t)
+	_, url := newScriptedServer(t, map[string][]scriptedReply{
+		"eth_sendRawTransaction":   {{status: http.StatusTooManyRequests}, {err: "nonce too low"}},
+		"eth_getTransactionByHash": {{result: nil}},
+	})
+	var hash common.Hash
+	err := dialRetrying(t, url).Call(&hash, "eth_sendRawTransaction", raw)
+	if err == nil || err.Error() != "nonce too low" {
+		t.Fatalf("err = %v, want nonce too low", err)
+	}
+}
@@ -0,0 +1,249 @@
+// Package simulated runs clients against an in-memory chain for end-to-end tests.
+// It is kept apart from package clients because it links a full go-ethereum node,
+// which is why New lives here rather than as a clients.NewSimulated.
+package simulated
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	gethsim "github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/mogza/abstract-go/clients"
+)
+
+// ChainID is the chain ID of every simulated chain.
+const ChainID = 1337
+
+var _ clients.RPCBackend = (*Backend)(nil)
+
+// Backend is an in-memory Ethereum chain, backed by go-ethereum's simulated backend.
+// Blocks are only mined by Commit, so tests control exactly when transactions land.
+// It runs the plain EVM: zkSync-specific RPCs and EIP-712 transactions are not available.
+type Backend struct {
+	sim       *gethsim.Backend
+	rpc       *rpc.Client
+	closeOnce sync.Once
+}
+
+// NewBackend starts a simulated chain whose genesis funds the accounts in alloc.
+// Pass it to clients.NewClientFromBackend to get a Client with native subscriptions.
+func NewBackend(alloc types.GenesisAlloc) (*Backend, error) {
+	// go-ethereum does not hand out the rpc connection behind the simulated client,
+	// so the node also serves IPC on a private endpoint and we dial that.
+	var endpoint string
+	sim := gethsim.NewBackend(alloc, func(nodeConf *node.Config, _ *ethconfig.Config) {
+		nodeConf.IPCPath = fmt.Sprintf("abstract-go-sim-%d-%d.ipc", os.Getpid(), ipcSeq.Add(1))
+		endpoint = nodeConf.IPCEndpoint()
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	rpcClient, err := rpc.DialIPC(ctx, endpoint)
+	if err != nil {
+		sim.Close()
+		return nil, fmt.Errorf("connect to simulated node: %w", err)
+	}
+	return &Backend{sim: sim, rpc: rpcClient}, nil
+}
+
+// dialTimeout bounds connecting to the simulated node's IPC endpoint.
+const dialTimeout = 10 * time.Second
+
+// ipcSeq keeps IPC endpoints unique between simulated chains of one process.
+var ipcSeq atomic.Uint64
+
+// Commit mines the pending transactions into a new block and returns its hash.
+func (b *Backend) Commit() common.Hash {
+	return b.sim.Commit()
+}
+
+// Rollback drops every pending transaction.
+func (b *Backend) Rollback() {
+	b.sim.Rollback()
+}
+
+// Fork starts a side chain at parent; once it outgrows the canonical chain, a reorg happens.
+func (b *Backend) Fork(parent common.Hash) error {
+	return b.sim.Fork(parent)
+}
+
+// AdjustTime moves the clock forward by d and mines an empty block.
+// Fails if transactions are pending.
+func (b *Backend) AdjustTime(d time.Duration) error {
+	return b.sim.AdjustTime(d)
+}
+
+// ChainID returns the chain ID of the simulated chain.
+func (b *Backend) ChainID() *big.Int {
+	return big.NewInt(ChainID)
+}
+
+// CallContext performs a JSON-RPC call against the simulated node.
+func (b *Backend) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return b.rpc.CallContext(ctx, result, method, args...)
+}
+
+// BatchCallContext performs a JSON-RPC batch against the simulated node.
+func (b *Backend) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	return b.rpc.BatchCallContext(ctx, batch)
+}
+
+// Close shuts the simulated chain down; it cannot be used afterwards.
+func (b *Backend) Close() {
+	b.closeOnce.Do(func() {
+		b.rpc.Close()
+		b.sim.Close()
+	})
+}
+
+// RPCClient returns the in-process connection to the simulated node.
+func (b *Backend) RPCClient() *rpc.Client {
+	return b.rpc
+}
+
+// DefaultMnemonic is the well-known development seed phrase the simulated wallets
+// are derived from. Never use it outside of tests.
+const DefaultMnemonic = "test test test test test test test test test test test junk"
+
+// DefaultAccounts is how many wallets New funds.
+const DefaultAccounts = 10
+
+// DefaultBalance is the genesis balance of each simulated wallet: 10,000 ETH.
+var DefaultBalance = new(big.Int).Mul(big.NewInt(10_000), big.NewInt(1e18))
+
+// Chain is a Client on an in-memory chain with funded wallets, for end-to-end tests.
+// Transactions stay pending until Commit mines them. Close shuts the chain down.
+type Chain struct {
+	*clients.Client
+
+	Backend *Backend
+
+	// Wallets are derived with clients.NewDeterministicWallet at indexes 0..n-1 and funded at genesis.
+	Wallets []*clients.Wallet
+
+	// NonceManagers holds one NonceManager per wallet, in the same order.
+	NonceManagers []*clients.NonceManager
+}
+
+// Option customizes the chain created by New.
+type Option func(*config)
+
+type config struct {
+	mnemonic string
+	accounts int
+	balance  *big.Int
+	alloc    types.GenesisAlloc
+	dialOpts []clients.DialOption
+}
+
+// WithMnemonic derives the wallets from mnemonic instead of DefaultMnemonic.
+func WithMnemonic(mnemonic string) Option {
+	return func(cfg *config) {
+		cfg.mnemonic = mnemonic
+	}
+}
+
+// WithAccounts sets how many wallets are derived and funded.
+func WithAccounts(n int) Option {
+	return func(cfg *config) {
+		cfg.accounts = n
+	}
+}
+
+// WithBalance sets the genesis balance of each wallet, in wei.
+func WithBalance(balance *big.Int) Option {
+	return func(cfg *config) {
+		cfg.balance = balance
+	}
+}
+
+// WithAlloc adds genesis accounts, such as pre-deployed contracts, to the chain.
+// Entries for wallet addresses replace the default funding.
+func WithAlloc(alloc types.GenesisAlloc) Option {
+	return func(cfg *config) {
+		cfg.alloc = alloc
+	}
+}
+
+// WithDialOptions applies client options, e.g. clients.WithLogger or clients.WithInstrumentation.
+func WithDialOptions(opts ...clients.DialOption) Option {
+	return func(cfg *config) {
+		cfg.dialOpts = append(cfg.dialOpts, opts...)
+	}
+}
+
+// New starts a simulated chain, funds DefaultAccounts wallets derived from
+// DefaultMnemonic and returns a Client on it. Calls, transactions and native
+// subscriptions all work; zkSync-specific RPCs and EIP-712 transactions do not.
+func New(opts ...Option) (*Chain, error) {
+	cfg := &config{
+		mnemonic: DefaultMnemonic,
+		accounts: DefaultAccounts,
+		balance:  DefaultBalance,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(cfg)
+		}
+	}
+
+	wallets := make([]*clients.Wallet, cfg.accounts)
+	alloc := make(types.GenesisAlloc, cfg.accounts+len(cfg.alloc))
+	for i := range wallets {
+		w, err := clients.NewDeterministicWallet(cfg.mnemonic, uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		wallets[i] = w
+		alloc[w.Address] = types.Account{Balance: new(big.Int).Set(cfg.balance)}
+	}
+	for addr, account := range cfg.alloc {
+		alloc[addr] = account
+	}
+
+	backend, err := NewBackend(alloc)
+	if err != nil {
+		return nil, err
+	}
+	client, err := clients.NewClientFromBackend(backend, cfg.dialOpts...)
+	if err != nil {
+		backend.Close()
+		return nil, err
+	}
+
+	nonces := make([]*clients.NonceManager, len(wallets))
+	for i, w := range wallets {
+		nonces[i] = clients.NewNonceManager(client, w.Address)
+	}
+	return &Chain{Client: client, Backend: backend, Wallets: wallets, NonceManagers: nonces}, nil
+}
+
+// Commit mines the pending transactions into a new block and returns its hash.
+func (c *Chain) Commit() common.Hash {
+	return c.Backend.Commit()
+}
+
+// CommitBlocks mines n blocks and returns the hash of the last one.
+func (c *Chain) CommitBlocks(n int) common.Hash {
+	var hash common.Hash
+	for i := 0; i < n; i++ {
+		hash = c.Backend.Commit()
+	}
+	return hash
+}
+
+// CommitTx mines the pending transactions and returns the receipt of tx.
+func (c *Chain) CommitTx(ctx context.Context, tx clients.Tx) (*types.Receipt, error) {
+	c.Backend.Commit()
+	return c.Eth.TransactionReceipt(ctx, tx.Hash())
+}
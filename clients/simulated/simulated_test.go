@@ -0,0 +1,335 @@
+package simulated
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/program"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mogza/abstract-go/clients"
+)
+
+func TestNewFundsWallets(t *testing.T) {
+	chain, err := New(WithAccounts(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Close()
+	ctx := context.Background()
+
+	if len(chain.Wallets) != 2 || len(chain.NonceManagers) != 2 {
+		t.Fatalf("got %d wallets and %d nonce managers, want 2", len(chain.Wallets), len(chain.NonceManagers))
+	}
+	balance, err := chain.Eth.BalanceAt(ctx, chain.Wallets[1].Address, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Cmp(DefaultBalance) != 0 {
+		t.Fatalf("balance = %s, want %s", balance, DefaultBalance)
+	}
+
+	to := chain.Wallets[1].Address
+	tx, err := chain.Wallets[0].BuildAndSendTx(ctx, chain.Client, &to, big.NewInt(1), nil, chain.NonceManagers[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := chain.CommitTx(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != 1 {
+		t.Fatalf("receipt status = %d, want 1", receipt.Status)
+	}
+}
+
+func TestNewFailsOnWrongChainID(t *testing.T) {
+	_, err := New(WithDialOptions(clients.WithExpectedChainID(1)))
+	if !errors.Is(err, clients.ErrChainIDMismatch) {
+		t.Fatalf("err = %v, want ErrChainIDMismatch", err)
+	}
+}
+
+func TestERC20AgainstSimulatedChain(t *testing.T) {
+	token := common.HexToAddress("0x00000000000000000000000000000000000e2c20")
+	owner, err := clients.NewDeterministicWallet(DefaultMnemonic, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := New(WithAccounts(2), WithAlloc(types.GenesisAlloc{
+		token: {Code: erc20Code(), Storage: map[common.Hash]common.Hash{
+			common.BytesToHash(owner.Address.Bytes()): common.BigToHash(big.NewInt(1000)),
+		}},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	recipient := chain.Wallets[1]
+
+	erc20, err := clients.NewERC20(chain.Client, token, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	decimals, err := erc20.Decimals(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decimals != 18 {
+		t.Fatalf("decimals = %d, want 18", decimals)
+	}
+	events := make(chan clients.ERC20TransferEvent, 1)
+	if err := erc20.WatchTransfers(ctx, &owner.Address, nil, events); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := erc20.Transfer(ctx, chain.Wallets[0], recipient.Address, big.NewInt(250))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt, err := chain.CommitTx(ctx, tx); err != nil || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("receipt = %v, %v; want a successful receipt", receipt, err)
+	}
+	for addr, want := range map[common.Address]int64{owner.Address: 750, recipient.Address: 250} {
+		balance, err := erc20.BalanceOf(ctx, addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if balance.Int64() != want {
+			t.Fatalf("balance of %s = %s, want %d", addr, balance, want)
+		}
+	}
+	select {
+	case ev := <-events:
+		if ev.From != owner.Address || ev.To != recipient.Address || ev.Value.Int64() != 250 {
+			t.Fatalf("event = %+v", ev)
+		}
+	case <-ctx.Done():
+		t.Fatal("no Transfer event")
+	}
+
+	if _, err := erc20.Transfer(ctx, recipient, owner.Address, big.NewInt(251)); err == nil {
+		t.Fatal("transfer over the balance: want an error")
+	}
+}
+
+func TestERC721AgainstSimulatedChain(t *testing.T) {
+	nft := common.HexToAddress("0x00000000000000000000000000000000000e2c21")
+	tokenID := big.NewInt(7)
+	owner, err := clients.NewDeterministicWallet(DefaultMnemonic, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := New(WithAccounts(2), WithAlloc(types.GenesisAlloc{
+		nft: {Code: erc721Code(), Storage: map[common.Hash]common.Hash{
+			common.BigToHash(tokenID): common.BytesToHash(owner.Address.Bytes()),
+		}},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	recipient := chain.Wallets[1]
+
+	erc721, err := clients.NewERC721(chain.Client, nft, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := make(chan clients.ERC721TransferEvent, 1)
+	if err := erc721.WatchTransfers(ctx, events); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := erc721.TransferFrom(ctx, chain.Wallets[0], owner.Address, recipient.Address, tokenID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt, err := chain.CommitTx(ctx, tx); err != nil || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("receipt = %v, %v; want a successful receipt", receipt, err)
+	}
+	got, err := erc721.OwnerOf(ctx, tokenID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != recipient.Address {
+		t.Fatalf("owner = %s, want %s", got, recipient.Address)
+	}
+	select {
+	case ev := <-events:
+		if ev.From != owner.Address || ev.To != recipient.Address || ev.TokenID.Cmp(tokenID) != 0 {
+			t.Fatalf("event = %+v", ev)
+		}
+	case <-ctx.Done():
+		t.Fatal("no Transfer event")
+	}
+
+	if _, err := erc721.TransferFrom(ctx, chain.Wallets[0], owner.Address, recipient.Address, tokenID); err == nil {
+		t.Fatal("transfer by the former owner: want an error")
+	}
+}
+
+func TestSubscriptionsAgainstSimulatedChain(t *testing.T) {
+	chain, err := New(WithAccounts(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	heads := make(chan *types.Header, 2)
+	headSub, err := chain.SubscribeNewHeads(ctx, heads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer headSub.Unsubscribe()
+	pending := make(chan common.Hash, 1)
+	pendingSub, err := chain.SubscribePendingTxs(ctx, pending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pendingSub.Unsubscribe()
+
+	to := chain.Wallets[1].Address
+	tx, err := chain.Wallets[0].BuildAndSendTx(ctx, chain.Client, &to, big.NewInt(1), nil, chain.NonceManagers[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case hash := <-pending:
+		if hash != tx.Hash() {
+			t.Fatalf("pending tx = %s, want %s", hash, tx.Hash())
+		}
+	case <-ctx.Done():
+		t.Fatal("no pending transaction")
+	}
+
+	chain.CommitBlocks(2)
+	for want := uint64(1); want <= 2; want++ {
+		select {
+		case head := <-heads:
+			if head.Number.Uint64() != want {
+				t.Fatalf("head = %d, want %d", head.Number, want)
+			}
+		case <-ctx.Done():
+			t.Fatalf("no head %d", want)
+		}
+	}
+}
+
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// erc20Code is the runtime code of a token with balanceOf, decimals and transfer.
+// The balance of an address is stored in the slot of the same number.
+func erc20Code() []byte {
+	a := newAssembler()
+	a.dispatch(map[string]uint32{
+		"balanceOf": 0x70a08231,
+		"decimals":  0x313ce567,
+		"transfer":  0xa9059cbb,
+	})
+
+	a.label("balanceOf")
+	a.Push(4).Op(vm.CALLDATALOAD, vm.SLOAD)
+	a.returnTop()
+
+	a.label("decimals")
+	a.Push(18)
+	a.returnTop()
+
+	a.label("transfer")
+	a.Op(vm.CALLER, vm.SLOAD).Push(36).Op(vm.CALLDATALOAD) // amount, balance
+	a.Op(vm.DUP1, vm.DUP3, vm.LT)
+	a.jumpIf("revert")
+	a.Op(vm.DUP1, vm.SWAP2, vm.SUB, vm.CALLER, vm.SSTORE)             // amount
+	a.Push(4).Op(vm.CALLDATALOAD, vm.DUP1, vm.SLOAD, vm.DUP3, vm.ADD) // balance+amount, to, amount
+	a.Op(vm.SWAP1, vm.SSTORE)
+	a.Push(0).Op(vm.MSTORE)
+	a.Push(4).Op(vm.CALLDATALOAD, vm.CALLER).Push(transferTopic).Push(32).Push(0).Op(vm.LOG3)
+	a.Push(1)
+	a.returnTop()
+
+	return a.code()
+}
+
+// erc721Code is the runtime code of a collection with ownerOf and transferFrom,
+// where only the owner may transfer. The owner of a token is stored in the slot of its ID.
+func erc721Code() []byte {
+	a := newAssembler()
+	a.dispatch(map[string]uint32{
+		"ownerOf":      0x6352211e,
+		"transferFrom": 0x23b872dd,
+	})
+
+	a.label("ownerOf")
+	a.Push(4).Op(vm.CALLDATALOAD, vm.SLOAD)
+	a.returnTop()
+
+	a.label("transferFrom")
+	a.Push(68).Op(vm.CALLDATALOAD, vm.SLOAD).Push(4).Op(vm.CALLDATALOAD) // from, owner
+	a.Op(vm.DUP1, vm.CALLER, vm.EQ, vm.SWAP2, vm.EQ, vm.AND, vm.ISZERO)
+	a.jumpIf("revert")
+	a.Push(36).Op(vm.CALLDATALOAD).Push(68).Op(vm.CALLDATALOAD, vm.SSTORE)
+	a.Push(68).Op(vm.CALLDATALOAD).Push(36).Op(vm.CALLDATALOAD).Push(4).Op(vm.CALLDATALOAD)
+	a.Push(transferTopic).Push(0).Push(0).Op(vm.LOG4, vm.STOP)
+
+	return a.code()
+}
+
+// assembler extends program.Program with named jump targets, resolved by code.
+type assembler struct {
+	*program.Program
+	labels map[string]int
+	jumps  map[int]string // offset of a two-byte jump target -> label
+}
+
+func newAssembler() *assembler {
+	return &assembler{Program: program.New(), labels: map[string]int{}, jumps: map[int]string{}}
+}
+
+// dispatch jumps to the label named after the function whose selector is called
+// and reverts for any other. It also emits the "revert" label.
+func (a *assembler) dispatch(selectors map[string]uint32) {
+	a.Push(0).Op(vm.CALLDATALOAD).Push(0xe0).Op(vm.SHR)
+	for name, selector := range selectors {
+		a.Op(vm.DUP1).Push(selector).Op(vm.EQ)
+		a.jumpIf(name)
+	}
+	a.label("revert")
+	a.Push(0).Op(vm.DUP1, vm.REVERT)
+}
+
+func (a *assembler) label(name string) {
+	a.labels[name] = a.Size()
+	a.Op(vm.JUMPDEST)
+}
+
+// jumpIf jumps to the label if the top of the stack is non-zero.
+func (a *assembler) jumpIf(name string) {
+	a.jumps[a.Size()+1] = name
+	a.Op(vm.PUSH2).Append([]byte{0, 0}).Op(vm.JUMPI)
+}
+
+// returnTop returns the top of the stack as one word.
+func (a *assembler) returnTop() {
+	a.Push(0).Op(vm.MSTORE).Push(32).Push(0).Op(vm.RETURN)
+}
+
+func (a *assembler) code() []byte {
+	code := a.Bytes()
+	for at, name := range a.jumps {
+		binary.BigEndian.PutUint16(code[at:], uint16(a.labels[name]))
+	}
+	return code
+}
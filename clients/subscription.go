@@ -3,9 +3,12 @@ package clients
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"math/big"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -16,6 +19,7 @@ import (
 
 type SubscriptionManager struct {
 	client *Client
+	logger *loggerRef
 	subs   []event.Subscription
 	wg     sync.WaitGroup
 	cancel context.CancelFunc
@@ -24,7 +28,21 @@ type SubscriptionManager struct {
 // NewSubscriptionManager creates a new SubscriptionManager for managing event subscriptions.
 // It holds references to the client, subscriptions, and manages goroutines.
 func NewSubscriptionManager(c *Client) *SubscriptionManager {
-	return &SubscriptionManager{client: c}
+	return &SubscriptionManager{client: c, logger: &loggerRef{}}
+}
+
+// SetLogger sets the logger for the manager's subscription goroutines.
+// A nil logger restores the client's logger.
+func (m *SubscriptionManager) SetLogger(logger *slog.Logger) {
+	m.logger.set(logger)
+}
+
+// Logger returns the logger the manager emits its messages to.
+func (m *SubscriptionManager) Logger() *slog.Logger {
+	if l := m.logger.p.Load(); l != nil {
+		return l
+	}
+	return m.client.Logger()
 }
 
 // Close cancels all active subscriptions and waits for goroutines to finish.
@@ -40,31 +58,101 @@ func (m *SubscriptionManager) Close() {
 }
 
 // SubscribeNewHeads subscribes to new block headers as soon as blocks are mined.
-// Requires a WebSocket connection; sends headers to the provided channel.
+// Uses a WebSocket subscription when available, otherwise polls; sends headers to the provided channel.
 func (c *Client) SubscribeNewHeads(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
-	if !c.isWS {
-		return nil, fmt.Errorf("SubscribeNewHeads requires a WebSocket connection")
+	if c.subEth == nil {
+		return c.pollNewHeads(ctx, ch)
 	}
-
-	return c.Eth.SubscribeNewHead(ctx, ch)
+	return c.subEth.SubscribeNewHead(ctx, ch)
 }
 
 // SubscribeLogs subscribes to smart contract event logs matching the given filter query.
-// Requires a WebSocket connection; sends logs to the provided channel.
+// Uses a WebSocket subscription when available, otherwise polls; sends logs to the provided channel.
 func (c *Client) SubscribeLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
-	if !c.isWS {
-		return nil, fmt.Errorf("SubscribeLogs requires a WebSocket connection")
+	if c.subEth == nil {
+		return c.pollLogs(ctx, query, ch, nil)
 	}
-	return c.Eth.SubscribeFilterLogs(ctx, query, ch)
+	return c.subEth.SubscribeFilterLogs(ctx, query, ch)
 }
 
 // SubscribePendingTxs subscribes to new transactions entering the mempool.
-// Requires a WebSocket connection; sends transaction hashes to the provided channel.
+// Uses a WebSocket subscription when available, otherwise polls a pending-tx filter.
 func (c *Client) SubscribePendingTxs(ctx context.Context, ch chan<- common.Hash) (ethereum.Subscription, error) {
-	if !c.isWS {
-		return nil, fmt.Errorf("SubscribePendingTxs requires a WebSocket connection")
+	if c.subRPC == nil {
+		return c.pollPendingTxs(ctx, ch)
+	}
+	return c.subRPC.EthSubscribe(ctx, ch, "newPendingTransactions")
+}
+
+// resubscribe keeps the subscription made by subscribe alive until ctx is done or it is
+// unsubscribed. After an error it logs the failure with the attempt number and subscribes
+// again every poll interval; only the first subscribe error is returned. args are logged
+// with every message, after the kind.
+func (c *Client) resubscribe(ctx context.Context, logger func() *slog.Logger, subscribe func(context.Context) (ethereum.Subscription, error), kind string, args ...any) (ethereum.Subscription, error) {
+	sub, err := subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	args = append([]any{LogKeyKind, kind}, args...)
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		attempt := 0 // consecutive failures
+		for {
+			var err error
+			select {
+			case err = <-sub.Err():
+			case <-quit:
+			case <-ctx.Done():
+			}
+			sub.Unsubscribe()
+			if err == nil {
+				return nil
+			}
+
+			for err != nil {
+				attempt++
+				c.instrumentation().SubscriptionEvent(ctx, kind, err)
+				logger().WarnContext(ctx, "subscription failed, resubscribing",
+					append(args[:len(args):len(args)], LogKeyAttempt, attempt, LogKeyError, err)...)
+
+				timer := time.NewTimer(c.pollEvery())
+				select {
+				case <-timer.C:
+				case <-quit:
+					timer.Stop()
+					return nil
+				case <-ctx.Done():
+					timer.Stop()
+					return nil
+				}
+				sub, err = subscribe(ctx)
+			}
+			logger().InfoContext(ctx, "resubscribed", append(args[:len(args):len(args)], LogKeyAttempt, attempt)...)
+			attempt = 0
+		}
+	}), nil
+}
+
+// resubscribeLogs is resubscribe for SubscribeLogs, logging to the client's logger.
+func (c *Client) resubscribeLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log, kind string, args ...any) (ethereum.Subscription, error) {
+	return c.resubscribe(ctx, c.Logger, c.subscribeLogsResuming(query, ch), kind, args...)
+}
+
+// subscribeLogsResuming returns the subscribe function resubscribe needs for logs. A polled
+// subscription that gave up restarts from the block after the last one it delivered, so
+// logs are neither delivered twice nor lost to the outage.
+func (c *Client) subscribeLogsResuming(query ethereum.FilterQuery, ch chan<- types.Log) func(context.Context) (ethereum.Subscription, error) {
+	var resume atomic.Uint64
+	return func(ctx context.Context) (ethereum.Subscription, error) {
+		if c.subEth != nil {
+			return c.subEth.SubscribeFilterLogs(ctx, query, ch)
+		}
+		q := query
+		if next := resume.Load(); next > 0 {
+			q.FromBlock = new(big.Int).SetUint64(next)
+		}
+		return c.pollLogs(ctx, q, ch, &resume)
 	}
-	return c.RpcClient.EthSubscribe(ctx, ch, "newPendingTransactions")
 }
 
 // --- Unified Event Watching ---
@@ -72,12 +160,8 @@ func (c *Client) SubscribePendingTxs(ctx context.Context, ch chan<- common.Hash)
 type EventHandler func(vLog types.Log) error
 
 // WatchContractEvent watches for a specific contract event with optional indexed filters.
-// Invokes the handler for each matching log; requires a WebSocket client.
+// Invokes the handler for each matching log; works over WebSocket or HTTP (polled).
 func (c *Client) WatchContractEvent(ctx context.Context, contractAddr common.Address, abiJSON string, eventName string, filter map[string][]common.Address, handler EventHandler) error {
-	if !c.isWS {
-		return fmt.Errorf("WatchContractEvent requires a WS client")
-	}
-
 	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
 	if err != nil {
 		return err
@@ -115,7 +199,7 @@ func (c *Client) WatchContractEvent(ctx context.Context, contractAddr common.Add
 	}
 
 	logsCh := make(chan types.Log)
-	sub, err := c.SubscribeLogs(ctx, query, logsCh)
+	sub, err := c.resubscribeLogs(ctx, query, logsCh, "contractEvent", LogKeyContract, contractAddr, "event", eventName)
 	if err != nil {
 		return err
 	}
@@ -124,12 +208,14 @@ func (c *Client) WatchContractEvent(ctx context.Context, contractAddr common.Add
 		defer sub.Unsubscribe()
 		for {
 			select {
-			case err := <-sub.Err():
-				fmt.Println("subscription error:", err)
+			case <-sub.Err():
 				return
 			case vLog := <-logsCh:
-				if err := handler(vLog); err != nil {
-					fmt.Println("handler error:", err)
+				err := handler(vLog)
+				c.instrumentation().SubscriptionEvent(ctx, "contractEvent", err)
+				if err != nil {
+					c.Logger().WarnContext(ctx, "event handler failed",
+						LogKeyKind, "contractEvent", LogKeyContract, contractAddr, "event", eventName, "tx", vLog.TxHash, LogKeyError, err)
 				}
 			case <-ctx.Done():
 				return
@@ -143,15 +229,13 @@ func (c *Client) WatchContractEvent(ctx context.Context, contractAddr common.Add
 // SubscribeNewHeads subscribes to new block headers and invokes the handler for each.
 // Manages subscription lifecycle and goroutine cleanup.
 func (m *SubscriptionManager) SubscribeNewHeads(handler func(*types.Header)) error {
-	if !m.client.isWS {
-		return fmt.Errorf("SubscribeNewHeads requires a WebSocket connection")
-	}
-
 	ctx, cancel := context.WithCancel(context.Background())
 	m.cancel = cancel
 
 	headers := make(chan *types.Header)
-	sub, err := m.client.SubscribeNewHeads(ctx, headers)
+	sub, err := m.client.resubscribe(ctx, m.Logger, func(ctx context.Context) (ethereum.Subscription, error) {
+		return m.client.SubscribeNewHeads(ctx, headers)
+	}, "newHeads")
 	if err != nil {
 		return err
 	}
@@ -162,10 +246,10 @@ func (m *SubscriptionManager) SubscribeNewHeads(handler func(*types.Header)) err
 		defer m.wg.Done()
 		for {
 			select {
-			case err := <-sub.Err():
-				log.Println("NewHeads subscription error:", err)
+			case <-sub.Err():
 				return
 			case header := <-headers:
+				m.client.instrumentation().SubscriptionEvent(ctx, "newHeads", nil)
 				handler(header)
 			case <-ctx.Done():
 				return
@@ -178,15 +262,11 @@ func (m *SubscriptionManager) SubscribeNewHeads(handler func(*types.Header)) err
 // SubscribeLogs subscribes to contract logs matching the filter and invokes the handler.
 // Manages subscription lifecycle and goroutine cleanup.
 func (m *SubscriptionManager) SubscribeLogs(query ethereum.FilterQuery, handler func(types.Log)) error {
-	if !m.client.isWS {
-		return fmt.Errorf("SubscribeLogs requires a WebSocket connection")
-	}
-
 	ctx, cancel := context.WithCancel(context.Background())
 	m.cancel = cancel
 
 	logsCh := make(chan types.Log)
-	sub, err := m.client.SubscribeLogs(ctx, query, logsCh)
+	sub, err := m.client.resubscribe(ctx, m.Logger, m.client.subscribeLogsResuming(query, logsCh), "logs", LogKeyContract, query.Addresses)
 	if err != nil {
 		return err
 	}
@@ -197,10 +277,10 @@ func (m *SubscriptionManager) SubscribeLogs(query ethereum.FilterQuery, handler
 		defer m.wg.Done()
 		for {
 			select {
-			case err := <-sub.Err():
-				log.Println("Logs subscription error:", err)
+			case <-sub.Err():
 				return
 			case vLog := <-logsCh:
+				m.client.instrumentation().SubscriptionEvent(ctx, "logs", nil)
 				handler(vLog)
 			case <-ctx.Done():
 				return
@@ -215,15 +295,13 @@ func (m *SubscriptionManager) SubscribeLogs(query ethereum.FilterQuery, handler
 // SubscribePendingTxs subscribes to new pending transactions and invokes the handler for each.
 // Manages subscription lifecycle and goroutine cleanup.
 func (m *SubscriptionManager) SubscribePendingTxs(handler func(common.Hash)) error {
-	if !m.client.isWS {
-		return fmt.Errorf("SubscribePendingTxs requires a WebSocket connection")
-	}
-
 	ctx, cancel := context.WithCancel(context.Background())
 	m.cancel = cancel
 
 	txCh := make(chan common.Hash)
-	sub, err := m.client.SubscribePendingTxs(ctx, txCh)
+	sub, err := m.client.resubscribe(ctx, m.Logger, func(ctx context.Context) (ethereum.Subscription, error) {
+		return m.client.SubscribePendingTxs(ctx, txCh)
+	}, "pendingTxs")
 	if err != nil {
 		return err
 	}
@@ -234,10 +312,10 @@ func (m *SubscriptionManager) SubscribePendingTxs(handler func(common.Hash)) err
 		defer m.wg.Done()
 		for {
 			select {
-			case err := <-sub.Err():
-				log.Println("PendingTxs subscription error:", err)
+			case <-sub.Err():
 				return
 			case tx := <-txCh:
+				m.client.instrumentation().SubscriptionEvent(ctx, "pendingTxs", nil)
 				handler(tx)
 			case <-ctx.Done():
 				return
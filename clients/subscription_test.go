@@ -0,0 +1,102 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// syncBuffer is a bytes.Buffer safe for a logger writing from another goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestResubscribeLogsAttempts(t *testing.T) {
+	var logs syncBuffer
+	client, err := NewClientFromBackend(NewFakeBackend(), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.SetPollInterval(time.Millisecond)
+
+	// The subscription fails once, then the first resubscribe fails and the second holds
+	var mu sync.Mutex
+	calls := 0
+	subscribe := func(ctx context.Context) (ethereum.Subscription, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		switch calls {
+		case 1:
+			return event.NewSubscription(func(<-chan struct{}) error { return errors.New("connection reset") }), nil
+		case 2:
+			return nil, errors.New("dial refused")
+		}
+		return event.NewSubscription(func(quit <-chan struct{}) error { <-quit; return nil }), nil
+	}
+	sub, err := client.resubscribe(context.Background(), client.Logger, subscribe, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(logs.String(), "resubscribed") {
+		if time.Now().After(deadline) {
+			t.Fatalf("no resubscription, logs:\n%s", logs.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	sub.Unsubscribe()
+	if err, ok := <-sub.Err(); ok || err != nil {
+		t.Fatalf("Err() = %v, want closed", err)
+	}
+
+	out := logs.String()
+	for _, want := range []string{
+		"attempt=1 error=\"connection reset\"",
+		"attempt=2 error=\"dial refused\"",
+		"msg=resubscribed kind=test attempt=2",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("logs missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestResubscribeReturnsFirstError(t *testing.T) {
+	client, err := NewClientFromBackend(NewFakeBackend())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	want := errors.New("no websocket")
+	_, err = client.resubscribe(context.Background(), client.Logger, func(context.Context) (ethereum.Subscription, error) {
+		return nil, want
+	}, "test")
+	if !errors.Is(err, want) {
+		t.Fatalf("err = %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,125 @@
+package clients
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Span is one timed operation of a trace. IDs follow the W3C Trace Context format.
+type Span struct {
+	Name     string
+	TraceID  string // 32 hex characters
+	SpanID   string // 16 hex characters
+	ParentID string // empty for a root span
+	Start    time.Time
+	End      time.Time
+	Attrs    map[string]string
+	Err      error
+}
+
+// Tracer is an Instrumentation that records a span for every RPC call and transaction send.
+// Spans nest through the context: an RPC made while sending a transaction is a child of the
+// send span, and both are children of any span started with StartSpan or ContextWithTraceParent.
+// The active span is forwarded to the node in a traceparent header.
+type Tracer struct {
+	onEnd func(Span)
+}
+
+type spanKey struct{}
+
+// spanContext identifies the active span in a context.
+type spanContext struct {
+	traceID string
+	spanID  string
+}
+
+// NewTracer creates a tracer that passes each finished span to onEnd.
+// onEnd may be called from several goroutines at once.
+func NewTracer(onEnd func(Span)) *Tracer {
+	return &Tracer{onEnd: onEnd}
+}
+
+// StartSpan starts a span as a child of the span in ctx, if any.
+// Call the returned function to finish it.
+func (t *Tracer) StartSpan(ctx context.Context, name string, attrs map[string]string) (context.Context, func(err error)) {
+	span := Span{
+		Name:   name,
+		SpanID: randomHex(8),
+		Start:  time.Now(),
+		Attrs:  attrs,
+	}
+	if parent, ok := ctx.Value(spanKey{}).(spanContext); ok {
+		span.TraceID = parent.traceID
+		span.ParentID = parent.spanID
+	} else {
+		span.TraceID = randomHex(16)
+	}
+
+	ctx = context.WithValue(ctx, spanKey{}, spanContext{traceID: span.TraceID, spanID: span.SpanID})
+	return ctx, func(err error) {
+		span.End = time.Now()
+		span.Err = err
+		if t.onEnd != nil {
+			t.onEnd(span)
+		}
+	}
+}
+
+// StartRPC starts an "rpc <method>" span.
+func (t *Tracer) StartRPC(ctx context.Context, method string) (context.Context, func(error)) {
+	return t.StartSpan(ctx, "rpc "+method, map[string]string{"rpc.method": method})
+}
+
+// StartTxSend starts a "tx.send" span tagged with the transaction hash, type and nonce.
+func (t *Tracer) StartTxSend(ctx context.Context, tx Tx) (context.Context, func(error)) {
+	return t.StartSpan(ctx, "tx.send", map[string]string{
+		"tx.hash":  tx.Hash().Hex(),
+		"tx.type":  strconv.Itoa(int(tx.Type())),
+		"tx.nonce": strconv.FormatUint(tx.Nonce(), 10),
+	})
+}
+
+// SubscriptionEvent does nothing; subscription events are not traced.
+func (t *Tracer) SubscriptionEvent(context.Context, string, error) {}
+
+// ContextWithTraceParent continues a trace received in a W3C traceparent header,
+// so spans recorded by the SDK join the caller's trace.
+func ContextWithTraceParent(ctx context.Context, traceparent string) (context.Context, error) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return ctx, fmt.Errorf("invalid traceparent %q", traceparent)
+	}
+	if _, err := hex.DecodeString(parts[1] + parts[2]); err != nil {
+		return ctx, fmt.Errorf("invalid traceparent %q", traceparent)
+	}
+	return context.WithValue(ctx, spanKey{}, spanContext{traceID: parts[1], spanID: parts[2]}), nil
+}
+
+// TraceParent returns the W3C traceparent value of the active span in ctx,
+// or false if there is none.
+func TraceParent(ctx context.Context) (string, bool) {
+	sc, ok := ctx.Value(spanKey{}).(spanContext)
+	if !ok {
+		return "", false
+	}
+	return "00-" + sc.traceID + "-" + sc.spanID + "-01", true
+}
+
+// injectTraceParent sets the traceparent header from the request context, if it has a span.
+func injectTraceParent(req *http.Request) {
+	if tp, ok := TraceParent(req.Context()); ok {
+		req.Header.Set("traceparent", tp)
+	}
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,94 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestTracerNestsSpansAndPropagatesTraceParent(t *testing.T) {
+	tx, _ := signedRawTx(t)
+	var (
+		mu           sync.Mutex
+		spans        []Span
+		traceparents []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		mu.Unlock()
+		reply := rpcMessage{Version: "2.0", ID: req.ID}
+		reply.Result, _ = json.Marshal(tx.Hash())
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(reply)
+	}))
+	defer srv.Close()
+
+	tracer := NewTracer(func(s Span) {
+		mu.Lock()
+		defer mu.Unlock()
+		spans = append(spans, s)
+	})
+	client, err := Dial(srv.URL, WithInstrumentation(tracer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	const incoming = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	ctx, err := ContextWithTraceParent(context.Background(), incoming)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want an rpc span and a send span", len(spans))
+	}
+	rpcSpan, send := spans[0], spans[1]
+	if send.Name != "tx.send" || send.Attrs["tx.hash"] != tx.Hash().Hex() || send.Attrs["tx.nonce"] != "7" {
+		t.Fatalf("send span = %+v", send)
+	}
+	if rpcSpan.Name != "rpc eth_sendRawTransaction" || rpcSpan.Attrs["rpc.method"] != "eth_sendRawTransaction" {
+		t.Fatalf("rpc span = %+v", rpcSpan)
+	}
+	const traceID = "0af7651916cd43dd8448eb211c80319c"
+	if send.TraceID != traceID || send.ParentID != "b7ad6b7169203331" {
+		t.Fatalf("send span joins %s/%s, want the incoming trace", send.TraceID, send.ParentID)
+	}
+	if rpcSpan.TraceID != traceID || rpcSpan.ParentID != send.SpanID {
+		t.Fatalf("rpc span is not a child of the send span: %+v", rpcSpan)
+	}
+	if len(traceparents) != 1 || traceparents[0] != "00-"+traceID+"-"+rpcSpan.SpanID+"-01" {
+		t.Fatalf("node saw traceparent %v, want the rpc span", traceparents)
+	}
+}
+
+func TestContextWithTraceParentRejectsMalformedValues(t *testing.T) {
+	for _, tp := range []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"00-0af7651916cd43dd8448eb211c8031-b7ad6b7169203331-01",
+		"00-zzf7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+	} {
+		ctx, err := ContextWithTraceParent(context.Background(), tp)
+		if err == nil {
+			t.Errorf("%q: want an error", tp)
+		}
+		if _, ok := TraceParent(ctx); ok {
+			t.Errorf("%q: the context carries a span", tp)
+		}
+	}
+}
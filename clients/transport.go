@@ -0,0 +1,54 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// rpcMessage is the subset of a JSON-RPC request or response the transports inspect.
+type rpcMessage struct {
+	Version string          `json:"jsonrpc,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// parseRPCMessages decodes a single JSON-RPC message or a batch of them.
+// batch reports whether the payload was a JSON array.
+func parseRPCMessages(body []byte) (msgs []rpcMessage, batch bool, err error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		err = json.Unmarshal(body, &msgs)
+		return msgs, true, err
+	}
+	var msg rpcMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, false, err
+	}
+	return []rpcMessage{msg}, false, nil
+}
+
+// rpcMethods returns the method names of a request payload, or nil if it cannot be parsed.
+func rpcMethods(body []byte) []string {
+	msgs, _, err := parseRPCMessages(body)
+	if err != nil {
+		return nil
+	}
+	methods := make([]string, len(msgs))
+	for i, m := range msgs {
+		methods[i] = m.Method
+	}
+	return methods
+}
@@ -0,0 +1,303 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tx is a signed transaction of either kind, as accepted by WaitMined and the instrumentation hooks.
+// It is either a *types.Transaction or, for native zkSync flows, a *Transaction712.
+type Tx interface {
+	Hash() common.Hash
+	Type() uint8
+	ChainId() *big.Int
+	Nonce() uint64
+	Gas() uint64
+	GasTipCap() *big.Int
+	GasFeeCap() *big.Int
+	To() *common.Address
+	Value() *big.Int
+	Data() []byte
+	MarshalBinary() ([]byte, error)
+}
+
+var (
+	_ Tx = (*types.Transaction)(nil)
+	_ Tx = (*Transaction712)(nil)
+)
+
+// DefaultGasBufferPercent is the headroom added on top of estimated gas.
+const DefaultGasBufferPercent = 10
+
+// Transaction types accepted by WithTxType.
+const (
+	TxTypeLegacy     = types.LegacyTxType
+	TxTypeDynamicFee = types.DynamicFeeTxType
+	TxTypeEIP712     = EIP712TxType
+)
+
+// TxOption customizes how a send helper builds its transaction.
+type TxOption func(*txConfig)
+
+type txConfig struct {
+	paymaster *PaymasterParams
+
+	txType      *uint8
+	gasLimit    uint64
+	gasBuffer   uint64
+	gasPrice    *big.Int
+	gasTipCap   *big.Int
+	gasFeeCap   *big.Int
+	feeStrategy FeeStrategy
+	nonce       *uint64
+	nm          *NonceManager
+	accessList  types.AccessList
+	chainID     *big.Int
+	buildOnly   bool
+}
+
+// WithPaymaster has the given paymaster pay for gas, sending an EIP-712 transaction.
+// Build the params with GeneralPaymasterParams or ApprovalBasedPaymasterParams.
+func WithPaymaster(params *PaymasterParams) TxOption {
+	return func(cfg *txConfig) {
+		cfg.paymaster = params
+	}
+}
+
+// WithTxType selects the transaction type: TxTypeLegacy, TxTypeDynamicFee (the default)
+// or TxTypeEIP712.
+func WithTxType(txType uint8) TxOption {
+	return func(cfg *txConfig) {
+		cfg.txType = &txType
+	}
+}
+
+// WithGasLimit sets the gas limit, skipping estimation and the gas buffer.
+func WithGasLimit(gas uint64) TxOption {
+	return func(cfg *txConfig) {
+		cfg.gasLimit = gas
+	}
+}
+
+// WithGasBuffer sets the percentage added to estimated gas; defaults to DefaultGasBufferPercent.
+func WithGasBuffer(percent uint64) TxOption {
+	return func(cfg *txConfig) {
+		cfg.gasBuffer = percent
+	}
+}
+
+// WithGasPrice sets the gas price of a legacy transaction instead of the node's suggestion.
+// Other transaction types reject it.
+func WithGasPrice(price *big.Int) TxOption {
+	return func(cfg *txConfig) {
+		cfg.gasPrice = price
+	}
+}
+
+// WithGasTipCap sets the priority fee per gas instead of the node's suggestion.
+func WithGasTipCap(tip *big.Int) TxOption {
+	return func(cfg *txConfig) {
+		cfg.gasTipCap = tip
+	}
+}
+
+// WithGasFeeCap sets the maximum fee per gas instead of deriving it from the node's suggestion.
+func WithGasFeeCap(feeCap *big.Int) TxOption {
+	return func(cfg *txConfig) {
+		cfg.gasFeeCap = feeCap
+	}
+}
+
+// WithNonce sends with an explicit nonce; no NonceManager is consulted.
+// Helpers sending several transactions use it for the first and count up from there.
+func WithNonce(nonce uint64) TxOption {
+	return func(cfg *txConfig) {
+		cfg.nonce = &nonce
+	}
+}
+
+// WithNonceManager takes nonces from nm, for helpers that do not accept one directly.
+func WithNonceManager(nm *NonceManager) TxOption {
+	return func(cfg *txConfig) {
+		cfg.nm = nm
+	}
+}
+
+// WithAccessList attaches an EIP-2930 access list to an EIP-1559 transaction.
+func WithAccessList(list types.AccessList) TxOption {
+	return func(cfg *txConfig) {
+		// Nodes reject tuples whose storage keys encode as null
+		cfg.accessList = make(types.AccessList, len(list))
+		for i, tuple := range list {
+			if tuple.StorageKeys == nil {
+				tuple.StorageKeys = []common.Hash{}
+			}
+			cfg.accessList[i] = tuple
+		}
+	}
+}
+
+// WithChainID signs for chainID without asking the node, e.g. for offline signing with
+// WithBuildOnly. It must match the client's expected or verified chain ID, if any.
+func WithChainID(chainID *big.Int) TxOption {
+	return func(cfg *txConfig) {
+		cfg.chainID = chainID
+	}
+}
+
+// WithBuildOnly builds and signs the transaction without sending it.
+// Nonces drawn from a NonceManager are consumed all the same.
+func WithBuildOnly() TxOption {
+	return func(cfg *txConfig) {
+		cfg.buildOnly = true
+	}
+}
+
+// newTxConfig applies the given options to the defaults.
+func newTxConfig(opts []TxOption) *txConfig {
+	cfg := &txConfig{gasBuffer: DefaultGasBufferPercent}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(cfg)
+		}
+	}
+	return cfg
+}
+
+// withNonceOffset returns opts with an explicit nonce, if any, advanced by offset.
+// Used by helpers that send several transactions with the same options.
+func withNonceOffset(opts []TxOption, offset uint64) []TxOption {
+	cfg := newTxConfig(opts)
+	if cfg.nonce == nil || offset == 0 {
+		return opts
+	}
+	return append(opts[:len(opts):len(opts)], WithNonce(*cfg.nonce+offset))
+}
+
+// resolveType returns the transaction type to build, checking it against the other options.
+func (cfg *txConfig) resolveType() (uint8, error) {
+	txType := uint8(TxTypeDynamicFee)
+	switch {
+	case cfg.txType != nil:
+		txType = *cfg.txType
+	case cfg.paymaster != nil:
+		txType = TxTypeEIP712
+	}
+	switch txType {
+	case TxTypeLegacy, TxTypeEIP712:
+		if len(cfg.accessList) > 0 {
+			return 0, fmt.Errorf("access lists are not supported by transaction type %d", txType)
+		}
+	case TxTypeDynamicFee:
+	default:
+		return 0, fmt.Errorf("unsupported transaction type %d", txType)
+	}
+	if cfg.paymaster != nil && txType != TxTypeEIP712 {
+		return 0, errors.New("paymasters require an EIP-712 transaction")
+	}
+	if cfg.gasPrice != nil && txType != TxTypeLegacy {
+		return 0, fmt.Errorf("gas price is not supported by transaction type %d; use WithGasTipCap and WithGasFeeCap", txType)
+	}
+	return txType, nil
+}
+
+// nextNonce returns the explicit nonce, else the next one from the NonceManager,
+// else the account's pending nonce.
+func (cfg *txConfig) nextNonce(ctx context.Context, client *Client, from common.Address, nm *NonceManager) (uint64, error) {
+	if cfg.nonce != nil {
+		return *cfg.nonce, nil
+	}
+	if nm == nil {
+		nm = cfg.nm
+	}
+	if nm != nil {
+		return nm.Next(ctx)
+	}
+	return client.NonceAt(ctx, from, AtPending())
+}
+
+// strategy returns the per-transaction fee strategy, else the client's.
+func (cfg *txConfig) strategy(client *Client) FeeStrategy {
+	if cfg.feeStrategy != nil {
+		return cfg.feeStrategy
+	}
+	return client.FeeStrategy()
+}
+
+// fees returns the tip and fee caps, taking whichever is not set explicitly from the fee strategy.
+// An explicit tip keeps the strategy's base fee allowance on top of it. meta is nil for
+// Ethereum transactions.
+func (cfg *txConfig) fees(ctx context.Context, client *Client, msg ethereum.CallMsg, meta *EIP712Meta) (tip, feeCap *big.Int, err error) {
+	tip, feeCap = cfg.gasTipCap, cfg.gasFeeCap
+	if tip == nil || feeCap == nil {
+		fees, err := cfg.strategy(client).Fees(ctx, client, msg, meta)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case tip == nil && feeCap == nil:
+			tip, feeCap = fees.GasTipCap, fees.GasFeeCap
+		case tip == nil:
+			tip = fees.GasTipCap
+			if tip.Cmp(feeCap) > 0 {
+				tip = new(big.Int).Set(feeCap)
+			}
+		default:
+			feeCap = new(big.Int).Sub(fees.GasFeeCap, fees.GasTipCap)
+			feeCap.Add(feeCap, tip)
+		}
+	}
+	if tip.Cmp(feeCap) > 0 {
+		return nil, nil, fmt.Errorf("tip cap %s exceeds fee cap %s", tip, feeCap)
+	}
+	return tip, feeCap, nil
+}
+
+// legacyGasPrice returns the explicit gas price, the price implied by a fee strategy
+// chosen for the transaction or client, or the node's suggestion.
+func (cfg *txConfig) legacyGasPrice(ctx context.Context, client *Client, msg ethereum.CallMsg) (*big.Int, error) {
+	if cfg.gasPrice != nil {
+		return cfg.gasPrice, nil
+	}
+	if cfg.feeStrategy == nil && client.feeStrategy.p.Load() == nil {
+		return client.Eth.SuggestGasPrice(ctx)
+	}
+	fees, err := cfg.strategy(client).Fees(ctx, client, msg, nil)
+	if err != nil {
+		return nil, err
+	}
+	if fees.BaseFee != nil {
+		return new(big.Int).Add(fees.BaseFee, fees.GasTipCap), nil
+	}
+	return fees.GasFeeCap, nil
+}
+
+// gas returns the explicit gas limit, or the estimate plus the gas buffer.
+func (cfg *txConfig) gas(estimate func() (uint64, error)) (uint64, error) {
+	if cfg.gasLimit != 0 {
+		return cfg.gasLimit, nil
+	}
+	gas, err := estimate()
+	if err != nil {
+		return 0, err
+	}
+	return gas + gas*cfg.gasBuffer/100, nil
+}
+
+// signingChainID returns the explicit chain ID or the one verified by the client.
+// An explicit chain ID fails with ErrChainIDMismatch if the client knows a different one.
+func (cfg *txConfig) signingChainID(ctx context.Context, client *Client) (*big.Int, error) {
+	if cfg.chainID == nil {
+		return client.ChainID(ctx)
+	}
+	if known := client.knownChainID(); known != nil && known.Cmp(cfg.chainID) != 0 {
+		return nil, fmt.Errorf("%w: signing for %s, client is on %s", ErrChainIDMismatch, cfg.chainID, known)
+	}
+	return cfg.chainID, nil
+}
@@ -0,0 +1,158 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// buildOnlyOpts builds a transaction without any node call but the chain ID check.
+func buildOnlyOpts(chainID int64) []TxOption {
+	return []TxOption{
+		WithBuildOnly(),
+		WithNonce(0),
+		WithGasLimit(21000),
+		WithGasTipCap(big.NewInt(1)),
+		WithGasFeeCap(big.NewInt(2)),
+		WithChainID(big.NewInt(chainID)),
+	}
+}
+
+func TestWithChainIDMustMatchExpectedChainID(t *testing.T) {
+	fake := NewFakeBackend().On("eth_chainId", "0xab5")
+	client, err := NewClientFromBackend(fake, WithExpectedChainID(2741))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	_, err = w.BuildAndSendTx(context.Background(), client, &to, big.NewInt(1), nil, nil, buildOnlyOpts(1)...)
+	if !errors.Is(err, ErrChainIDMismatch) {
+		t.Fatalf("err = %v, want ErrChainIDMismatch", err)
+	}
+
+	tx, err := w.BuildAndSendTx(context.Background(), client, &to, big.NewInt(1), nil, nil, buildOnlyOpts(2741)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.ChainId().Int64() != 2741 {
+		t.Fatalf("signed for chain %s, want 2741", tx.ChainId())
+	}
+}
+
+func TestWithChainIDSignsOfflineWithoutKnownChainID(t *testing.T) {
+	fake := NewFakeBackend()
+	client, err := NewClientFromBackend(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	tx, err := w.BuildAndSendTx(context.Background(), client, &to, big.NewInt(1), nil, nil, buildOnlyOpts(1)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.ChainId().Int64() != 1 || len(fake.Calls()) != 0 {
+		t.Fatalf("signed for chain %s after %d calls, want chain 1 and no calls", tx.ChainId(), len(fake.Calls()))
+	}
+}
+
+func TestBuildAndSendTx712RejectsNonEIP712Options(t *testing.T) {
+	client, err := NewClientFromBackend(NewFakeBackend())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	for name, opt := range map[string]TxOption{
+		"WithTxType":     WithTxType(TxTypeLegacy),
+		"WithGasPrice":   WithGasPrice(big.NewInt(1)),
+		"WithAccessList": WithAccessList(types.AccessList{{Address: to}}),
+	} {
+		opts := append(buildOnlyOpts(2741), opt)
+		if _, err := w.BuildAndSendTx712(context.Background(), client, &to, big.NewInt(1), nil, nil, nil, opts...); err == nil {
+			t.Errorf("%s: want an error", name)
+		}
+	}
+	if _, err := w.BuildAndSendTx(context.Background(), client, &to, big.NewInt(1), nil, nil, append(buildOnlyOpts(2741), WithGasPrice(big.NewInt(1)))...); err == nil {
+		t.Error("WithGasPrice on an EIP-1559 transaction: want an error")
+	}
+}
+
+func TestSafeContractCallAppliesTxOptions(t *testing.T) {
+	fake := NewFakeBackend().On("eth_call", "0x")
+	client, err := NewClientFromBackend(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	const abiJSON = `[{"type":"function","name":"setValue","inputs":[{"name":"v","type":"uint256"}],"outputs":[]}]`
+
+	tx, err := w.SafeContractCall(context.Background(), client, contract, abiJSON, "setValue", nil,
+		[]interface{}{big.NewInt(42)}, append(buildOnlyOpts(2741), WithNonce(7))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 7 || tx.Gas() != 21000 {
+		t.Fatalf("nonce %d gas %d, want the options' 7 and 21000", tx.Nonce(), tx.Gas())
+	}
+	if n := len(fake.Calls()); n != 1 {
+		t.Fatalf("%d calls, want only the simulation", n)
+	}
+}
+
+func TestFailedBuildKeepsNonce(t *testing.T) {
+	fake := NewFakeBackend().
+		On("eth_getTransactionCount", "0x5").
+		OnError("eth_estimateGas", errors.New("execution reverted"))
+	client, err := NewClientFromBackend(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	w, err := NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nm := NewNonceManager(client, w.Address)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	if _, err := w.BuildAndSendTx(context.Background(), client, &to, big.NewInt(1), nil, nm, WithChainID(big.NewInt(2741))); err == nil {
+		t.Fatal("want the estimate error")
+	}
+	if _, err := w.BuildAndSendTx712(context.Background(), client, &to, big.NewInt(1), nil, nm, nil,
+		WithChainID(big.NewInt(2741)), WithGasTipCap(big.NewInt(1)), WithGasFeeCap(big.NewInt(2))); err == nil {
+		t.Fatal("want the estimate error")
+	}
+	nonce, err := nm.Next(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce != 5 {
+		t.Fatalf("next nonce = %d, want 5", nonce)
+	}
+}
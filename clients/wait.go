@@ -0,0 +1,253 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrTxReverted is returned by WaitMined when the transaction was mined but failed.
+var ErrTxReverted = errors.New("transaction reverted")
+
+// MinedResult describes a mined transaction once it has enough confirmations.
+type MinedResult struct {
+	TxHash        common.Hash
+	Receipt       *types.Receipt
+	Status        uint64 // types.ReceiptStatusSuccessful or types.ReceiptStatusFailed
+	BlockNumber   uint64
+	BlockHash     common.Hash
+	Confirmations uint64 // blocks on top of and including the inclusion block
+
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int // nil if the node does not report it
+	Fee               *big.Int // GasUsed * EffectiveGasPrice; nil if the price is unknown
+
+	// Reorgs counts how often the transaction left the canonical chain while waiting.
+	Reorgs int
+
+	// RevertReason is the decoded reason of a failed transaction, e.g. the Error(string)
+	// message or the Panic code; RevertData holds the raw revert data if any.
+	RevertReason string
+	RevertData   []byte
+}
+
+// Succeeded reports whether the transaction executed successfully.
+func (r *MinedResult) Succeeded() bool {
+	return r.Status == types.ReceiptStatusSuccessful
+}
+
+// WaitOption customizes how WaitMined waits.
+type WaitOption func(*waitConfig)
+
+type waitConfig struct {
+	confirmations uint64
+	timeout       time.Duration
+	pollInterval  time.Duration
+	newHeads      *bool
+}
+
+// WithConfirmations waits until the inclusion block has n-1 blocks on top of it.
+// Defaults to 1: the transaction is in a block.
+func WithConfirmations(n uint64) WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.confirmations = n
+	}
+}
+
+// WithWaitTimeout gives up after d. Without it WaitMined waits until ctx is done.
+func WithWaitTimeout(d time.Duration) WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.timeout = d
+	}
+}
+
+// WithWaitPolling checks for the receipt every interval instead of on new heads.
+func WithWaitPolling(interval time.Duration) WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.pollInterval = interval
+		polling := false
+		cfg.newHeads = &polling
+	}
+}
+
+// WithWaitNewHeads checks for the receipt on every new head. This is the default when
+// the client has native subscriptions; over HTTP heads are polled.
+func WithWaitNewHeads() WaitOption {
+	return func(cfg *waitConfig) {
+		heads := true
+		cfg.newHeads = &heads
+	}
+}
+
+// WaitMined waits until tx is mined with the required confirmations and returns its result.
+// If a reorg drops the transaction or moves it to another block, waiting starts over.
+// A failed transaction returns its result with an error wrapping ErrTxReverted.
+func (c *Client) WaitMined(ctx context.Context, tx Tx, opts ...WaitOption) (*MinedResult, error) {
+	cfg := &waitConfig{confirmations: 1, pollInterval: c.pollEvery()}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(cfg)
+		}
+	}
+	if cfg.confirmations == 0 {
+		cfg.confirmations = 1
+	}
+	if cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.timeout)
+		defer cancel()
+	}
+	hash := tx.Hash()
+
+	// Wake up on every new head, or on every poll when heads are unavailable
+	var heads chan *types.Header
+	var headErr <-chan error
+	if cfg.newHeads == nil && c.subRPC != nil || cfg.newHeads != nil && *cfg.newHeads {
+		heads = make(chan *types.Header, 16)
+		sub, err := c.SubscribeNewHeads(ctx, heads)
+		if err != nil {
+			c.Logger().WarnContext(ctx, "new heads unavailable, polling for receipt",
+				"tx", hash, LogKeyError, err)
+			heads = nil
+		} else {
+			defer sub.Unsubscribe()
+			headErr = sub.Err()
+		}
+	}
+	ticker := time.NewTicker(cfg.pollInterval)
+	defer ticker.Stop()
+	if heads != nil {
+		ticker.Stop()
+	}
+
+	var (
+		seen   *types.Receipt
+		reorgs int
+	)
+	for {
+		receipt, err := c.Eth.TransactionReceipt(ctx, hash)
+		switch {
+		case errors.Is(err, ethereum.NotFound):
+			if seen != nil {
+				reorgs++
+				c.Logger().WarnContext(ctx, "transaction dropped by reorg, waiting again",
+					"tx", hash, "block", seen.BlockNumber)
+				seen = nil
+			}
+		case err != nil:
+			if ctx.Err() == nil {
+				c.Logger().DebugContext(ctx, "fetching receipt failed", "tx", hash, LogKeyError, err)
+			}
+		default:
+			if seen != nil && seen.BlockHash != receipt.BlockHash {
+				reorgs++
+				c.Logger().WarnContext(ctx, "transaction moved by reorg",
+					"tx", hash, "from", seen.BlockNumber, "to", receipt.BlockNumber)
+			}
+			seen = receipt
+
+			head, err := c.Eth.BlockNumber(ctx)
+			if err == nil && head >= receipt.BlockNumber.Uint64() {
+				confirmations := head - receipt.BlockNumber.Uint64() + 1
+				if confirmations >= cfg.confirmations {
+					return c.minedResult(ctx, tx, receipt, confirmations, reorgs)
+				}
+			}
+		}
+
+		select {
+		case <-heads:
+		case err := <-headErr:
+			// The subscription died; keep going by polling
+			c.Logger().WarnContext(ctx, "new heads subscription failed, polling for receipt",
+				"tx", hash, LogKeyError, err)
+			heads, headErr = nil, nil
+			ticker.Reset(cfg.pollInterval)
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for transaction %s: %w", hash.Hex(), ctx.Err())
+		}
+	}
+}
+
+// minedResult builds the result of a mined transaction, decoding the revert reason if it failed.
+func (c *Client) minedResult(ctx context.Context, tx Tx, receipt *types.Receipt, confirmations uint64, reorgs int) (*MinedResult, error) {
+	res := &MinedResult{
+		TxHash:            receipt.TxHash,
+		Receipt:           receipt,
+		Status:            receipt.Status,
+		BlockNumber:       receipt.BlockNumber.Uint64(),
+		BlockHash:         receipt.BlockHash,
+		Confirmations:     confirmations,
+		GasUsed:           receipt.GasUsed,
+		EffectiveGasPrice: receipt.EffectiveGasPrice,
+		Reorgs:            reorgs,
+	}
+	if res.EffectiveGasPrice != nil {
+		res.Fee = new(big.Int).Mul(res.EffectiveGasPrice, new(big.Int).SetUint64(res.GasUsed))
+	}
+	if res.Succeeded() {
+		return res, nil
+	}
+
+	res.RevertReason, res.RevertData = c.revertReason(ctx, tx, receipt)
+	if res.RevertReason == "" {
+		return res, fmt.Errorf("%w: %s", ErrTxReverted, res.TxHash.Hex())
+	}
+	return res, fmt.Errorf("%w: %s: %s", ErrTxReverted, res.TxHash.Hex(), res.RevertReason)
+}
+
+// revertReason replays a failed transaction on the state before its block and decodes
+// the revert data. The replay ignores earlier transactions of the same block, so the
+// reason is best effort; it is empty if the call does not revert again.
+func (c *Client) revertReason(ctx context.Context, tx Tx, receipt *types.Receipt) (string, []byte) {
+	from, err := txSender(tx)
+	if err != nil {
+		return "", nil
+	}
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	parent := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	_, err = c.CallContract(ctx, msg, AtBlock(parent))
+	if err == nil {
+		return "", nil
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if hexData, ok := dataErr.ErrorData().(string); ok {
+			if data, decodeErr := hexutil.Decode(hexData); decodeErr == nil {
+				if reason, unpackErr := abi.UnpackRevert(data); unpackErr == nil {
+					return reason, data
+				}
+				return err.Error(), data
+			}
+		}
+	}
+	return err.Error(), nil
+}
+
+// txSender returns the sender of a signed transaction.
+func txSender(tx Tx) (common.Address, error) {
+	switch tx := tx.(type) {
+	case *Transaction712:
+		return tx.From(), nil
+	case *types.Transaction:
+		return types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	}
+	return common.Address{}, fmt.Errorf("unsupported transaction type %T", tx)
+}
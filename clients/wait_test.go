@@ -0,0 +1,130 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// minedReceipt returns the receipt of tx in block number with the given hash.
+func minedReceipt(tx *types.Transaction, number int64, block common.Hash, status uint64) *types.Receipt {
+	return &types.Receipt{
+		Status:            status,
+		TxHash:            tx.Hash(),
+		BlockHash:         block,
+		BlockNumber:       big.NewInt(number),
+		GasUsed:           21000,
+		CumulativeGasUsed: 21000,
+		EffectiveGasPrice: big.NewInt(2),
+		Logs:              []*types.Log{},
+	}
+}
+
+func waitClient(t *testing.T, fake *FakeBackend) *Client {
+	t.Helper()
+	client, err := NewClientFromBackend(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestWaitMinedWaitsForConfirmations(t *testing.T) {
+	tx, _ := signedRawTx(t)
+	block := common.HexToHash("0xb1")
+	fake := NewFakeBackend().
+		Once("eth_getTransactionReceipt", nil).
+		On("eth_getTransactionReceipt", minedReceipt(tx, 10, block, types.ReceiptStatusSuccessful)).
+		Once("eth_blockNumber", "0xa").
+		Once("eth_blockNumber", "0xb").
+		On("eth_blockNumber", "0xc")
+	client := waitClient(t, fake)
+
+	res, err := client.WaitMined(context.Background(), tx, WithConfirmations(3), WithWaitPolling(time.Millisecond), WithWaitTimeout(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Succeeded() || res.BlockNumber != 10 || res.BlockHash != block || res.Confirmations != 3 || res.Reorgs != 0 {
+		t.Fatalf("result = %+v, want block 10 with 3 confirmations", res)
+	}
+	if res.Fee == nil || res.Fee.Int64() != 42000 {
+		t.Fatalf("fee = %v, want 42000", res.Fee)
+	}
+	if n := len(fake.CallsTo("eth_blockNumber")); n != 3 {
+		t.Fatalf("%d head checks, want 3", n)
+	}
+}
+
+func TestWaitMinedFollowsReorgs(t *testing.T) {
+	tx, _ := signedRawTx(t)
+	a, b := common.HexToHash("0xa1"), common.HexToHash("0xb1")
+	// Mined in block 10 (a), moved to block 11 (b), dropped, then mined in b again
+	fake := NewFakeBackend().
+		Once("eth_getTransactionReceipt", minedReceipt(tx, 10, a, types.ReceiptStatusSuccessful)).
+		Once("eth_getTransactionReceipt", minedReceipt(tx, 11, b, types.ReceiptStatusSuccessful)).
+		Once("eth_getTransactionReceipt", nil).
+		On("eth_getTransactionReceipt", minedReceipt(tx, 11, b, types.ReceiptStatusSuccessful)).
+		Once("eth_blockNumber", "0xa").
+		Once("eth_blockNumber", "0xb").
+		On("eth_blockNumber", "0xc")
+	client := waitClient(t, fake)
+
+	res, err := client.WaitMined(context.Background(), tx, WithConfirmations(2), WithWaitPolling(time.Millisecond), WithWaitTimeout(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.BlockHash != b || res.BlockNumber != 11 || res.Confirmations != 2 || res.Reorgs != 2 {
+		t.Fatalf("result = %+v, want block 11 after 2 reorgs", res)
+	}
+}
+
+func TestWaitMinedDecodesRevertReason(t *testing.T) {
+	tx, _ := signedRawTx(t)
+	fake := NewFakeBackend().
+		On("eth_getTransactionReceipt", minedReceipt(tx, 10, common.HexToHash("0xb1"), types.ReceiptStatusFailed)).
+		On("eth_blockNumber", "0xa").
+		OnError("eth_call", &FixtureError{Code: 3, Message: "execution reverted: no", Data: json.RawMessage(`"` + revertData + `"`)})
+	client := waitClient(t, fake)
+
+	res, err := client.WaitMined(context.Background(), tx, WithWaitPolling(time.Millisecond), WithWaitTimeout(5*time.Second))
+	if !errors.Is(err, ErrTxReverted) {
+		t.Fatalf("err = %v, want ErrTxReverted", err)
+	}
+	if res == nil || res.Succeeded() || res.RevertReason != "no" || len(res.RevertData) == 0 {
+		t.Fatalf("result = %+v, want the reason \"no\"", res)
+	}
+
+	// The call replays the transaction from its sender on the parent block
+	calls := fake.CallsTo("eth_call")
+	if len(calls) != 1 || string(calls[0].Params[1]) != `"0x9"` {
+		t.Fatalf("eth_call = %+v, want one at block 0x9", calls)
+	}
+	var msg struct{ From common.Address }
+	if err := json.Unmarshal(calls[0].Params[0], &msg); err != nil {
+		t.Fatal(err)
+	}
+	from, err := txSender(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.From != from {
+		t.Fatalf("replayed from %s, want the sender %s", msg.From, from)
+	}
+}
+
+func TestWaitMinedTimesOut(t *testing.T) {
+	tx, _ := signedRawTx(t)
+	client := waitClient(t, NewFakeBackend().On("eth_getTransactionReceipt", nil))
+
+	_, err := client.WaitMined(context.Background(), tx, WithWaitPolling(time.Millisecond), WithWaitTimeout(20*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
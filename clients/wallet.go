@@ -24,47 +24,152 @@ type Wallet struct {
 }
 
 // BuildAndSendTx creates, signs, and sends an EIP-1559 ETH transaction.
-// It estimates gas, sets fees, and uses the provided NonceManager.
-func (w *Wallet) BuildAndSendTx(ctx context.Context, client *Client, to *common.Address, value *big.Int, data []byte, nm *NonceManager) (*types.Transaction, error) {
-	if client.isWS {
-		return nil, fmt.Errorf("BuildAndSendTx requires an HTTP connection, not WebSocket")
+// It estimates gas (+10%), prices it with the client's FeeStrategy and takes the nonce
+// from nm, or the pending nonce if nm is nil. TxOptions override each of these, select
+// a legacy or EIP-712 transaction or build without sending.
+// The result is a *types.Transaction, or a *Transaction712 when sent with WithPaymaster
+// or WithTxType(TxTypeEIP712).
+func (w *Wallet) BuildAndSendTx(ctx context.Context, client *Client, to *common.Address, value *big.Int, data []byte, nm *NonceManager, opts ...TxOption) (Tx, error) {
+	cfg := newTxConfig(opts)
+	txType, err := cfg.resolveType()
+	if err != nil {
+		return nil, err
+	}
+	if txType == TxTypeEIP712 {
+		return w.BuildAndSendTx712(ctx, client, to, value, data, nm, nil, opts...)
 	}
 
-	// Get next nonce safely
-	nonce, err := nm.Next(ctx)
+	// Estimate gas with optional buffer
+	msg := ethereum.CallMsg{
+		From:       w.Address,
+		To:         to,
+		Value:      value,
+		Data:       data,
+		AccessList: cfg.accessList,
+	}
+	gasLimit, err := cfg.gas(func() (uint64, error) {
+		return client.EstimateGasWithBuffer(ctx, msg, 0)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Gas suggestion
-	gasTipCap, err := client.Eth.SuggestGasTipCap(ctx)
+	chainID, err := cfg.signingChainID(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var gasPrice, gasTipCap, maxFee *big.Int
+	if txType == TxTypeLegacy {
+		gasPrice, err = cfg.legacyGasPrice(ctx, client, msg)
+	} else {
+		gasTipCap, maxFee, err = cfg.fees(ctx, client, msg, nil)
+	}
 	if err != nil {
 		return nil, err
 	}
-	baseFee, err := client.Eth.SuggestGasPrice(ctx)
+
+	// Draw the nonce last, so that a failure above does not leave a gap in the NonceManager
+	nonce, err := cfg.nextNonce(ctx, client, w.Address, nm)
 	if err != nil {
 		return nil, err
 	}
-	maxFee := new(big.Int).Add(baseFee, gasTipCap)
 
-	// Estimate gas with optional buffer
+	var tx *types.Transaction
+	if txType == TxTypeLegacy {
+		tx = types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: gasPrice,
+			Gas:      gasLimit,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		})
+	} else {
+		tx = types.NewTx(&types.DynamicFeeTx{
+			ChainID:    chainID,
+			Nonce:      nonce,
+			GasTipCap:  gasTipCap,
+			GasFeeCap:  maxFee,
+			Gas:        gasLimit,
+			To:         to,
+			Value:      value,
+			Data:       data,
+			AccessList: cfg.accessList,
+		})
+	}
+
+	signedTx, err := types.SignTx(tx, types.NewLondonSigner(chainID), w.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.buildOnly {
+		return signedTx, nil
+	}
+
+	if err := client.SendTransaction(ctx, signedTx); err != nil {
+		return nil, err
+	}
+
+	return signedTx, nil
+}
+
+// BuildAndSendTx712 creates, signs, and sends a native zkSync EIP-712 transaction.
+// meta may be nil; gas is estimated with the meta attached since it affects the result.
+// TxOptions apply as for BuildAndSendTx; WithPaymaster fills in a meta without paymaster.
+func (w *Wallet) BuildAndSendTx712(ctx context.Context, client *Client, to *common.Address, value *big.Int, data []byte, nm *NonceManager, meta *EIP712Meta, opts ...TxOption) (*Transaction712, error) {
+	cfg := newTxConfig(opts)
+	if cfg.txType == nil {
+		txType := uint8(TxTypeEIP712)
+		cfg.txType = &txType
+	}
+	if txType, err := cfg.resolveType(); err != nil {
+		return nil, err
+	} else if txType != TxTypeEIP712 {
+		return nil, fmt.Errorf("BuildAndSendTx712 cannot send transaction type %d", txType)
+	}
+	if meta == nil {
+		meta = &EIP712Meta{}
+	}
+	if meta.PaymasterParams == nil && cfg.paymaster != nil {
+		withPaymaster := *meta
+		withPaymaster.PaymasterParams = cfg.paymaster
+		meta = &withPaymaster
+	}
+
 	msg := ethereum.CallMsg{
 		From:  w.Address,
 		To:    to,
 		Value: value,
 		Data:  data,
 	}
-	gasLimit, err := client.EstimateGasWithBuffer(ctx, msg, 10) // +10% buffer
+
+	// Gas suggestion
+	gasTipCap, maxFee, err := cfg.fees(ctx, client, msg, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit, err := cfg.gas(func() (uint64, error) {
+		return client.EstimateGas712(ctx, msg, meta)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The EIP-712 domain is bound to the chain ID, not the network ID
+	chainID, err := cfg.signingChainID(ctx, client)
 	if err != nil {
 		return nil, err
 	}
 
-	chainID, err := client.Eth.NetworkID(ctx)
+	// Draw the nonce last, so that a failure above does not leave a gap in the NonceManager
+	nonce, err := cfg.nextNonce(ctx, client, w.Address, nm)
 	if err != nil {
 		return nil, err
 	}
 
-	tx := types.NewTx(&types.DynamicFeeTx{
+	tx := NewTransaction712(&EIP712Tx{
 		ChainID:   chainID,
 		Nonce:     nonce,
 		GasTipCap: gasTipCap,
@@ -73,20 +178,47 @@ func (w *Wallet) BuildAndSendTx(ctx context.Context, client *Client, to *common.
 		To:        to,
 		Value:     value,
 		Data:      data,
+		From:      w.Address,
+		Meta:      *meta,
 	})
 
-	signedTx, err := types.SignTx(tx, types.NewLondonSigner(chainID), w.PrivateKey)
+	signedTx, err := w.SignTx712(tx)
 	if err != nil {
 		return nil, err
 	}
+	if cfg.buildOnly {
+		return signedTx, nil
+	}
 
-	if err := client.SendTransaction(ctx, signedTx); err != nil {
+	if err := client.SendTransaction712(ctx, signedTx); err != nil {
 		return nil, err
 	}
 
 	return signedTx, nil
 }
 
+// SignTx712 signs an EIP-712 transaction with the zkSync typed-data domain.
+// Returns a copy of the transaction carrying the signature as its customSignature.
+func (w *Wallet) SignTx712(tx *Transaction712) (*Transaction712, error) {
+	if w == nil || w.PrivateKey == nil {
+		return nil, errors.New("wallet or private key nil")
+	}
+	if tx.From() != w.Address {
+		return nil, fmt.Errorf("transaction sender %s does not match wallet %s", tx.From().Hex(), w.Address.Hex())
+	}
+	digest, err := tx.SigningHash()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := crypto.Sign(digest.Bytes(), w.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	// Normalize V to 27/28 as expected by the zkSync account validation
+	sig[64] += 27
+	return tx.WithSignature(sig), nil
+}
+
 // ExportKeystoreJSON exports the wallet as an encrypted keystore JSON.
 // The output is compatible with go-ethereum and requires a password.
 func (w *Wallet) ExportKeystoreJSON(password string) ([]byte, error) {
@@ -156,7 +288,8 @@ func (w *Wallet) ExportPrivateKeyHex() string {
 
 // ApproveAndTransferERC20 approves a spender and then transfers ERC20 tokens.
 // Returns both the approve and transfer transactions, or an error.
-func (w *Wallet) ApproveAndTransferERC20(ctx context.Context, client *Client, token, recipient, spender common.Address, amount *big.Int, nm *NonceManager) (*types.Transaction, *types.Transaction, error) {
+// TxOptions apply to both; an explicit nonce is used for the approval and incremented for the transfer.
+func (w *Wallet) ApproveAndTransferERC20(ctx context.Context, client *Client, token, recipient, spender common.Address, amount *big.Int, nm *NonceManager, opts ...TxOption) (Tx, Tx, error) {
 
 	erc20Token, err := NewERC20(client, token, "")
 	if err != nil {
@@ -164,13 +297,16 @@ func (w *Wallet) ApproveAndTransferERC20(ctx context.Context, client *Client, to
 	}
 
 	// Approve spender
-	approveTx, err := erc20Token.Approve(ctx, w, spender, amount)
+	if nm != nil {
+		opts = append([]TxOption{WithNonceManager(nm)}, opts...)
+	}
+	approveTx, err := erc20Token.Approve(ctx, w, spender, amount, opts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	// Transfer tokens from spender to recipient
-	transferTx, err := erc20Token.TransferFrom(ctx, w, spender, recipient, amount)
+	transferTx, err := erc20Token.TransferFrom(ctx, w, spender, recipient, amount, withNonceOffset(opts, 1)...)
 	if err != nil {
 		return approveTx, nil, err
 	}
@@ -180,16 +316,17 @@ func (w *Wallet) ApproveAndTransferERC20(ctx context.Context, client *Client, to
 
 // BatchSendETH sends ETH to multiple recipients in a batch.
 // Returns a slice of transactions or an error if any send fails.
-func (w *Wallet) BatchSendETH(ctx context.Context, client *Client, recipients []common.Address, amounts []*big.Int, nm *NonceManager) ([]*types.Transaction, error) {
+// TxOptions apply to every transaction; an explicit nonce is used for the first and incremented.
+func (w *Wallet) BatchSendETH(ctx context.Context, client *Client, recipients []common.Address, amounts []*big.Int, nm *NonceManager, opts ...TxOption) ([]Tx, error) {
 
 	if len(recipients) != len(amounts) {
 		return nil, fmt.Errorf("recipients and amounts length mismatch")
 	}
 
-	var txs []*types.Transaction
+	var txs []Tx
 
 	for i, to := range recipients {
-		tx, err := w.BuildAndSendTx(ctx, client, &to, amounts[i], nil, nm)
+		tx, err := w.BuildAndSendTx(ctx, client, &to, amounts[i], nil, nm, withNonceOffset(opts, uint64(i))...)
 		if err != nil {
 			return txs, err
 		}
@@ -200,15 +337,25 @@ func (w *Wallet) BatchSendETH(ctx context.Context, client *Client, recipients []
 }
 
 // SafeContractCall safely calls a contract method with ABI encoding.
-// Simulates the call before sending the transaction to the network.
-func (w *Wallet) SafeContractCall(ctx context.Context, client *Client, contract common.Address, abiJSON string, method string, nm *NonceManager, params ...interface{}) (*types.Transaction, error) {
+// Simulates the call with args before sending the transaction with the given TxOptions
+// (e.g. WithGasLimit or WithPaymaster).
+func (w *Wallet) SafeContractCall(ctx context.Context, client *Client, contract common.Address, abiJSON string, method string, nm *NonceManager, args []interface{}, opts ...TxOption) (Tx, error) {
+	data, err := w.simulateContractCall(ctx, client, contract, abiJSON, method, args)
+	if err != nil {
+		return nil, err
+	}
+	return w.BuildAndSendTx(ctx, client, &contract, big.NewInt(0), data, nm, opts...)
+}
 
+// simulateContractCall encodes a contract call and simulates it from the wallet.
+// Returns the calldata to send.
+func (w *Wallet) simulateContractCall(ctx context.Context, client *Client, contract common.Address, abiJSON string, method string, args []interface{}) ([]byte, error) {
 	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := parsedABI.Pack(method, params...)
+	data, err := parsedABI.Pack(method, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -222,11 +369,5 @@ func (w *Wallet) SafeContractCall(ctx context.Context, client *Client, contract
 	if _, err := client.CallContract(ctx, msg); err != nil {
 		return nil, fmt.Errorf("simulation failed: %w", err)
 	}
-
-	tx, err := w.BuildAndSendTx(ctx, client, &contract, big.NewInt(0), data, nm)
-	if err != nil {
-		return nil, err
-	}
-
-	return tx, nil
+	return data, nil
 }
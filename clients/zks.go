@@ -0,0 +1,361 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Transaction statuses reported by zks_getTransactionDetails.
+const (
+	TxStatusPending  = "pending"
+	TxStatusIncluded = "included"
+	TxStatusVerified = "verified"
+	TxStatusFailed   = "failed"
+)
+
+// Block and batch statuses reported by zks_getBlockDetails and zks_getL1BatchDetails.
+const (
+	BlockStatusSealed   = "sealed"
+	BlockStatusVerified = "verified"
+)
+
+// BaseSystemContractsHashes are the bytecode hashes of the bootloader and default account.
+type BaseSystemContractsHashes struct {
+	Bootloader  common.Hash  `json:"bootloader"`
+	DefaultAA   common.Hash  `json:"default_aa"`
+	EVMEmulator *common.Hash `json:"evm_emulator,omitempty"`
+}
+
+// L1BatchDetails describes an L1 batch and its commit, prove and execute progress on L1.
+type L1BatchDetails struct {
+	Number                    uint64                    `json:"number"`
+	Timestamp                 uint64                    `json:"timestamp"`
+	L1TxCount                 uint64                    `json:"l1TxCount"`
+	L2TxCount                 uint64                    `json:"l2TxCount"`
+	RootHash                  *common.Hash              `json:"rootHash"`
+	Status                    string                    `json:"status"`
+	CommitTxHash              *common.Hash              `json:"commitTxHash"`
+	CommittedAt               *time.Time                `json:"committedAt"`
+	ProveTxHash               *common.Hash              `json:"proveTxHash"`
+	ProvenAt                  *time.Time                `json:"provenAt"`
+	ExecuteTxHash             *common.Hash              `json:"executeTxHash"`
+	ExecutedAt                *time.Time                `json:"executedAt"`
+	L1GasPrice                *big.Int                  `json:"l1GasPrice"`
+	L2FairGasPrice            *big.Int                  `json:"l2FairGasPrice"`
+	FairPubdataPrice          *big.Int                  `json:"fairPubdataPrice"`
+	BaseSystemContractsHashes BaseSystemContractsHashes `json:"baseSystemContractsHashes"`
+}
+
+// BlockDetails describes an L2 block and the L1 progress of the batch that contains it.
+type BlockDetails struct {
+	Number                    uint64                    `json:"number"`
+	L1BatchNumber             uint64                    `json:"l1BatchNumber"`
+	Timestamp                 uint64                    `json:"timestamp"`
+	L1TxCount                 uint64                    `json:"l1TxCount"`
+	L2TxCount                 uint64                    `json:"l2TxCount"`
+	RootHash                  *common.Hash              `json:"rootHash"`
+	Status                    string                    `json:"status"`
+	CommitTxHash              *common.Hash              `json:"commitTxHash"`
+	CommittedAt               *time.Time                `json:"committedAt"`
+	ProveTxHash               *common.Hash              `json:"proveTxHash"`
+	ProvenAt                  *time.Time                `json:"provenAt"`
+	ExecuteTxHash             *common.Hash              `json:"executeTxHash"`
+	ExecutedAt                *time.Time                `json:"executedAt"`
+	L1GasPrice                *big.Int                  `json:"l1GasPrice"`
+	L2FairGasPrice            *big.Int                  `json:"l2FairGasPrice"`
+	FairPubdataPrice          *big.Int                  `json:"fairPubdataPrice"`
+	BaseSystemContractsHashes BaseSystemContractsHashes `json:"baseSystemContractsHashes"`
+	OperatorAddress           common.Address            `json:"operatorAddress"`
+	ProtocolVersion           string                    `json:"protocolVersion"`
+}
+
+// TransactionDetails describes the status of an L2 transaction and its L1 settlement.
+type TransactionDetails struct {
+	IsL1Originated   bool           `json:"isL1Originated"`
+	Status           string         `json:"status"`
+	Fee              *hexutil.Big   `json:"fee"`
+	GasPerPubdata    *hexutil.Big   `json:"gasPerPubdata"`
+	InitiatorAddress common.Address `json:"initiatorAddress"`
+	ReceivedAt       time.Time      `json:"receivedAt"`
+	EthCommitTxHash  *common.Hash   `json:"ethCommitTxHash"`
+	EthProveTxHash   *common.Hash   `json:"ethProveTxHash"`
+	EthExecuteTxHash *common.Hash   `json:"ethExecuteTxHash"`
+}
+
+// Fee is the result of zks_estimateFee.
+type Fee struct {
+	GasLimit             *hexutil.Big `json:"gas_limit"`
+	MaxFeePerGas         *hexutil.Big `json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas *hexutil.Big `json:"max_priority_fee_per_gas"`
+	GasPerPubdataLimit   *hexutil.Big `json:"gas_per_pubdata_limit"`
+}
+
+// BridgeContracts lists the default bridge addresses; unset bridges are nil.
+type BridgeContracts struct {
+	L1Erc20DefaultBridge  *common.Address `json:"l1Erc20DefaultBridge"`
+	L2Erc20DefaultBridge  *common.Address `json:"l2Erc20DefaultBridge"`
+	L1WethBridge          *common.Address `json:"l1WethBridge"`
+	L2WethBridge          *common.Address `json:"l2WethBridge"`
+	L1SharedDefaultBridge *common.Address `json:"l1SharedDefaultBridge"`
+	L2SharedDefaultBridge *common.Address `json:"l2SharedDefaultBridge"`
+	L2LegacySharedBridge  *common.Address `json:"l2LegacySharedBridge"`
+}
+
+// FeeParams is the result of zks_getFeeParams; exactly one version is set.
+type FeeParams struct {
+	V1 *FeeParamsV1 `json:"V1,omitempty"`
+	V2 *FeeParamsV2 `json:"V2,omitempty"`
+}
+
+// FeeModelConfigV1 is the fee model configuration of FeeParamsV1.
+type FeeModelConfigV1 struct {
+	MinimalL2GasPrice *big.Int `json:"minimal_l2_gas_price"`
+}
+
+// FeeParamsV1 are the legacy fee model parameters.
+type FeeParamsV1 struct {
+	Config     FeeModelConfigV1 `json:"config"`
+	L1GasPrice *big.Int         `json:"l1_gas_price"`
+}
+
+// FeeModelConfigV2 is the fee model configuration of FeeParamsV2.
+type FeeModelConfigV2 struct {
+	MinimalL2GasPrice   *big.Int `json:"minimal_l2_gas_price"`
+	ComputeOverheadPart float64  `json:"compute_overhead_part"`
+	PubdataOverheadPart float64  `json:"pubdata_overhead_part"`
+	BatchOverheadL1Gas  uint64   `json:"batch_overhead_l1_gas"`
+	MaxGasPerBatch      uint64   `json:"max_gas_per_batch"`
+	MaxPubdataPerBatch  uint64   `json:"max_pubdata_per_batch"`
+}
+
+// FeeParamsV2 are the current fee model parameters.
+type FeeParamsV2 struct {
+	Config          FeeModelConfigV2 `json:"config"`
+	L1GasPrice      *big.Int         `json:"l1_gas_price"`
+	L1PubdataPrice  *big.Int         `json:"l1_pubdata_price"`
+	ConversionRatio struct {
+		Numerator   uint64 `json:"numerator"`
+		Denominator uint64 `json:"denominator"`
+	} `json:"conversion_ratio"`
+}
+
+// RawTransactionExecute is the execution payload of a raw block transaction.
+type RawTransactionExecute struct {
+	ContractAddress *common.Address `json:"contractAddress"`
+	Calldata        hexutil.Bytes   `json:"calldata"`
+	Value           *hexutil.Big    `json:"value"`
+	FactoryDeps     []rpcBytes      `json:"factoryDeps"`
+}
+
+// RawBlockTransaction is an entry of zks_getRawBlockTransactions.
+// CommonData is kept raw since its shape depends on the L1/L2 origin.
+type RawBlockTransaction struct {
+	CommonData          json.RawMessage       `json:"common_data"`
+	Execute             RawTransactionExecute `json:"execute"`
+	ReceivedTimestampMs uint64                `json:"received_timestamp_ms"`
+	RawBytes            *hexutil.Bytes        `json:"raw_bytes"`
+}
+
+// BlockDetails returns details of an L2 block (zks_getBlockDetails).
+// Returns ethereum.NotFound if the block does not exist.
+func (c *Client) BlockDetails(ctx context.Context, number uint64) (*BlockDetails, error) {
+	var details *BlockDetails
+	if err := c.zksCall(ctx, &details, "zks_getBlockDetails", number); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// L1BatchDetails returns details of an L1 batch (zks_getL1BatchDetails).
+// Returns ethereum.NotFound if the batch does not exist.
+func (c *Client) L1BatchDetails(ctx context.Context, batch uint64) (*L1BatchDetails, error) {
+	var details *L1BatchDetails
+	if err := c.zksCall(ctx, &details, "zks_getL1BatchDetails", batch); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// TransactionDetails returns the status of an L2 transaction (zks_getTransactionDetails).
+// Returns ethereum.NotFound if the node does not know the transaction.
+func (c *Client) TransactionDetails(ctx context.Context, txHash common.Hash) (*TransactionDetails, error) {
+	var details *TransactionDetails
+	if err := c.zksCall(ctx, &details, "zks_getTransactionDetails", txHash); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// EstimateFee estimates gas limit and fees for a call (zks_estimateFee).
+// meta may be nil; pass it to account for factory deps or a paymaster.
+func (c *Client) EstimateFee(ctx context.Context, msg ethereum.CallMsg, meta *EIP712Meta) (*Fee, error) {
+	var fee *Fee
+	if err := c.zksCall(ctx, &fee, "zks_estimateFee", toCallArg(msg, meta)); err != nil {
+		return nil, err
+	}
+	return fee, nil
+}
+
+// BridgeContracts returns the default bridge addresses: those of the Network the client
+// was dialed with, if it lists them, else zks_getBridgeContracts, fetched once and cached.
+func (c *Client) BridgeContracts(ctx context.Context) (*BridgeContracts, error) {
+	if c.network != nil && c.network.Bridges != nil {
+		return c.network.Bridges, nil
+	}
+	c.bridges.mu.Lock()
+	defer c.bridges.mu.Unlock()
+
+	if c.bridges.fetched == nil {
+		var bridges *BridgeContracts
+		if err := c.zksCall(ctx, &bridges, "zks_getBridgeContracts"); err != nil {
+			return nil, err
+		}
+		c.bridges.fetched = bridges
+	}
+	return c.bridges.fetched, nil
+}
+
+// bridgeCache holds the bridge addresses fetched from the node.
+type bridgeCache struct {
+	mu      sync.Mutex
+	fetched *BridgeContracts
+}
+
+// L1ChainID returns the chain ID of the underlying L1 (zks_L1ChainId).
+func (c *Client) L1ChainID(ctx context.Context) (*big.Int, error) {
+	var id hexutil.Big
+	if err := c.zksCall(ctx, &id, "zks_L1ChainId"); err != nil {
+		return nil, err
+	}
+	return id.ToInt(), nil
+}
+
+// L1BatchNumber returns the number of the latest sealed L1 batch (zks_L1BatchNumber).
+func (c *Client) L1BatchNumber(ctx context.Context) (uint64, error) {
+	var number hexutil.Uint64
+	if err := c.zksCall(ctx, &number, "zks_L1BatchNumber"); err != nil {
+		return 0, err
+	}
+	return uint64(number), nil
+}
+
+// AllAccountBalances returns every token balance of an address (zks_getAllAccountBalances).
+// The map is keyed by token address.
+func (c *Client) AllAccountBalances(ctx context.Context, addr common.Address) (map[common.Address]*big.Int, error) {
+	var raw map[common.Address]*hexutil.Big
+	if err := c.zksCall(ctx, &raw, "zks_getAllAccountBalances", addr); err != nil {
+		return nil, err
+	}
+	balances := make(map[common.Address]*big.Int, len(raw))
+	for token, balance := range raw {
+		balances[token] = balance.ToInt()
+	}
+	return balances, nil
+}
+
+// FeeParams returns the current fee model parameters (zks_getFeeParams).
+func (c *Client) FeeParams(ctx context.Context) (*FeeParams, error) {
+	var params *FeeParams
+	if err := c.zksCall(ctx, &params, "zks_getFeeParams"); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// BytecodeByHash returns the bytecode for a versioned bytecode hash (zks_getBytecodeByHash).
+// Returns ethereum.NotFound if the hash is unknown.
+func (c *Client) BytecodeByHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	var code rpcBytes
+	if err := c.zksCall(ctx, &code, "zks_getBytecodeByHash", hash); err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// RawBlockTransactions returns the raw transactions of an L2 block (zks_getRawBlockTransactions).
+func (c *Client) RawBlockTransactions(ctx context.Context, number uint64) ([]RawBlockTransaction, error) {
+	var txs []RawBlockTransaction
+	if err := c.zksCall(ctx, &txs, "zks_getRawBlockTransactions", number); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// rpcBytes decodes bytes sent either as a hex string or as an array of numbers.
+// The ZK Stack node uses both encodings depending on the endpoint.
+type rpcBytes []byte
+
+func (b *rpcBytes) UnmarshalJSON(input []byte) error {
+	if len(input) > 0 && input[0] == '"' {
+		var h hexutil.Bytes
+		if err := h.UnmarshalJSON(input); err != nil {
+			return err
+		}
+		*b = rpcBytes(h)
+		return nil
+	}
+	var raw []int
+	if err := json.Unmarshal(input, &raw); err != nil {
+		return err
+	}
+	out := make([]byte, len(raw))
+	for i, n := range raw {
+		if n < 0 || n > 255 {
+			return fmt.Errorf("byte value %d out of range", n)
+		}
+		out[i] = byte(n)
+	}
+	*b = out
+	return nil
+}
+
+// zksCall performs a zks_ RPC call and decodes the result.
+// A null result is reported as ethereum.NotFound.
+func (c *Client) zksCall(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	var raw json.RawMessage
+	if err := c.RpcClient.CallContext(ctx, &raw, method, args...); err != nil {
+		return err
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return ethereum.NotFound
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// toCallArg builds the JSON call request accepted by eth_call, eth_estimateGas and zks_estimateFee.
+func toCallArg(msg ethereum.CallMsg, meta *EIP712Meta) map[string]interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+	}
+	if msg.To != nil {
+		arg["to"] = msg.To
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasFeeCap != nil {
+		arg["maxFeePerGas"] = (*hexutil.Big)(msg.GasFeeCap)
+	}
+	if msg.GasTipCap != nil {
+		arg["maxPriorityFeePerGas"] = (*hexutil.Big)(msg.GasTipCap)
+	}
+	if meta != nil {
+		arg["type"] = hexutil.Uint64(EIP712TxType)
+		arg["eip712Meta"] = encodeEIP712Meta(meta)
+	}
+	return arg
+}
@@ -12,7 +12,7 @@ import (
 func main() {
 	ctx := context.Background()
 
-	client, _ := clients.DialHTTP("https://api.testnet.abs.xyz")
+	client, _ := clients.DialNetwork(clients.AbstractTestnet)
 	defer client.Eth.Close()
 	wallet, _ := clients.FromPrivateKey("YOUR_PRIVATE_KEY")
 
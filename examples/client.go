@@ -12,7 +12,7 @@ import (
 func main() {
 	ctx := context.Background()
 
-	client, err := clients.DialHTTP("https://api.testnet.abs.xyz")
+	client, err := clients.DialNetwork(clients.AbstractTestnet)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mogza/abstract-go/clients"
+)
+
+func main() {
+	ctx := context.Background()
+
+	client, err := clients.DialNetwork(clients.AbstractTestnet)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Close()
+
+	wallet, err := clients.FromPrivateKey("YOUR_WALLET_PRIVATE_KEY")
+	if err != nil {
+		log.Fatal(err)
+	}
+	nm := clients.NewNonceManager(client, wallet.Address)
+
+	// zksolc artifact (e.g. artifacts-zk/contracts/Counter.sol/Counter.json)
+	artifact, err := clients.LoadArtifactFile("Counter.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	addr, tx, err := wallet.DeployContract(ctx, client, artifact, nm, []interface{}{big.NewInt(1)})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("✅ Deployed at:", addr.Hex(), "tx:", tx.Hash().Hex())
+
+	// Deterministic address with CREATE2
+	salt := common.HexToHash("0x01")
+	addr2, tx2, err := wallet.DeployContractCreate2(ctx, client, artifact, salt, nm, []interface{}{big.NewInt(1)})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("✅ CREATE2 deployed at:", addr2.Hex(), "tx:", tx2.Hash().Hex())
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mogza/abstract-go/clients"
+)
+
+func main() {
+	ctx := context.Background()
+
+	client, err := clients.DialNetwork(clients.AbstractTestnet)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Close()
+
+	wallet, err := clients.FromPrivateKey("YOUR_WALLET_PRIVATE_KEY")
+	if err != nil {
+		log.Fatal(err)
+	}
+	nm := clients.NewNonceManager(client, wallet.Address)
+
+	recipient := common.HexToAddress("RECIPIENT_ADDRESS")
+	amount := big.NewInt(0)
+	amount.SetString("10000000000000000", 10) // 0.01 ETH
+
+	// Native zkSync (type 0x71) transaction with default gasPerPubdata
+	tx, err := wallet.BuildAndSendTx712(ctx, client, &recipient, amount, nil, nm, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("✅ EIP-712 transaction sent! Tx hash:", tx.Hash().Hex())
+}
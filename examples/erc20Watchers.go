@@ -11,7 +11,7 @@ import (
 func main() {
 	ctx := context.Background()
 
-	wsClient, _ := clients.DialWS("wss://api.testnet.abs.xyz/ws")
+	wsClient, _ := clients.DialWS(clients.AbstractTestnet.WSURL)
 	defer wsClient.Close()
 
 	token := common.HexToAddress("ERC20_TOKEN_ADDRESS")
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mogza/abstract-go/clients"
+)
+
+func main() {
+	ctx := context.Background()
+
+	client, err := clients.DialNetwork(clients.AbstractTestnet)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Close()
+
+	txHash := common.HexToHash("YOUR_TX_HASH")
+	updates, err := client.TrackFinality(ctx, txHash)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for update := range updates {
+		if update.Err != nil {
+			log.Println("poll error:", update.Err)
+			continue
+		}
+		fmt.Println("⛓ Stage:", update.Stage)
+		if update.L1TxHash != nil {
+			fmt.Println("   L1 tx:", update.L1TxHash.Hex())
+		}
+	}
+}
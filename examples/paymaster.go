@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mogza/abstract-go/clients"
+)
+
+func main() {
+	ctx := context.Background()
+
+	client, err := clients.DialNetwork(clients.AbstractTestnet)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Close()
+
+	wallet, err := clients.FromPrivateKey("YOUR_WALLET_PRIVATE_KEY")
+	if err != nil {
+		log.Fatal(err)
+	}
+	nm := clients.NewNonceManager(client, wallet.Address)
+
+	paymaster := common.HexToAddress("PAYMASTER_ADDRESS")
+	recipient := common.HexToAddress("RECIPIENT_ADDRESS")
+
+	// General flow: the paymaster sponsors the whole fee
+	general, err := clients.GeneralPaymasterParams(paymaster, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	tx, err := wallet.BuildAndSendTx(ctx, client, &recipient, big.NewInt(0), nil, nm, clients.WithPaymaster(general))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("✅ Sponsored tx sent:", tx.Hash().Hex())
+
+	// Approval-based flow: the fee is paid in an ERC20 token
+	feeToken := common.HexToAddress("FEE_TOKEN_ADDRESS")
+	approval, err := clients.ApprovalBasedPaymasterParams(paymaster, feeToken, big.NewInt(1), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	token, err := clients.NewERC20(client, feeToken, "")
+	if err != nil {
+		log.Fatal(err)
+	}
+	tx, err = token.Transfer(ctx, wallet, recipient, big.NewInt(1000), clients.WithPaymaster(approval))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("✅ ERC20 transfer paid in tokens:", tx.Hash().Hex())
+}
@@ -12,7 +12,7 @@ import (
 func main() {
 	ctx := context.Background()
 
-	client, _ := clients.DialHTTP("https://api.testnet.abs.xyz")
+	client, _ := clients.DialNetwork(clients.AbstractTestnet)
 	defer client.Eth.Close()
 	wallet, _ := clients.FromPrivateKey("YOUR_PRIVATE_KEY")
 
@@ -21,7 +21,7 @@ func main() {
 	contract := common.HexToAddress("0xContractAddress")
 	abiJSON := `[{"inputs":[{"internalType":"uint256","name":"value","type":"uint256"}],"name":"setValue","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
 
-	tx, err := wallet.SafeContractCall(ctx, client, contract, abiJSON, "setValue", nm, big.NewInt(42))
+	tx, err := wallet.SafeContractCall(ctx, client, contract, abiJSON, "setValue", nm, []interface{}{big.NewInt(42)})
 	if err != nil {
 		fmt.Println("SafeContractCall failed:", err)
 	} else {
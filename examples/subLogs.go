@@ -12,7 +12,7 @@ import (
 )
 
 func main() {
-	client, err := clients.DialWS("wss://api.testnet.abs.xyz")
+	client, err := clients.DialWS(clients.AbstractTestnet.WSURL)
 	if err != nil {
 		log.Fatal(err)
 	}
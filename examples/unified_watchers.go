@@ -12,7 +12,7 @@ import (
 
 func main() {
 	ctx := context.Background()
-	wsClient, err := clients.DialWS("wss://api.testnet.abs.xyz/ws")
+	wsClient, err := clients.DialWS(clients.AbstractTestnet.WSURL)
 	if err != nil {
 		panic(err)
 	}